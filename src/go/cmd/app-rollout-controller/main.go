@@ -21,10 +21,14 @@ import (
 	"flag"
 	"log"
 	"net/http"
+	// Embed time zone data for maintenance windows as the image may not
+	// ship it.
+	_ "time/tzdata"
 
 	apps "github.com/SAP/cloud-robotics/src/go/pkg/apis/apps/v1alpha1"
 	config "github.com/SAP/cloud-robotics/src/go/pkg/apis/config/v1alpha1"
 	registry "github.com/SAP/cloud-robotics/src/go/pkg/apis/registry/v1alpha1"
+	"github.com/SAP/cloud-robotics/src/go/pkg/controller/apprelease"
 	"github.com/SAP/cloud-robotics/src/go/pkg/controller/approllout"
 	"github.com/pkg/errors"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -96,11 +100,14 @@ func setupAppV2(ctx context.Context, cfg *rest.Config, params map[string]interfa
 	if err := approllout.Add(ctx, mgr, chartutil.Values(params)); err != nil {
 		return errors.Wrap(err, "add AppRollout controller")
 	}
+	if err := apprelease.Add(mgr); err != nil {
+		return errors.Wrap(err, "add AppRelease controller")
+	}
 
 	srv := mgr.GetWebhookServer()
 	srv.CertDir = *certDir
 
-	srv.Register("/approllout/validate", approllout.NewValidationWebhook(mgr))
+	srv.Register("/approllout/validate", approllout.NewValidationWebhook(mgr, chartutil.Values(params)))
 
 	go func() {
 		if err := mgr.Start(signals.SetupSignalHandler()); err != nil {
@@ -27,6 +27,7 @@ import (
 	apps "github.com/SAP/cloud-robotics/src/go/pkg/apis/apps/v1alpha1"
 	"github.com/SAP/cloud-robotics/src/go/pkg/controller/chartassignment"
 	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/rest"
@@ -47,6 +48,9 @@ var (
 	webhookEnabled = flag.Bool("webhook-enabled", true,
 		"Whether the webhook should be served")
 
+	webhookRenderCharts = flag.Bool("webhook-render-charts", false,
+		"Whether the webhook should render charts and reject ChartAssignments whose charts fail to render")
+
 	webhookPort = flag.Int("webhook-port", 9876,
 		"Listening port of the custom resource webhook")
 
@@ -85,6 +89,7 @@ func main() {
 		log.Fatalln(err)
 	}
 
+	http.Handle("/metrics", promhttp.Handler())
 	// Run a k8s liveness probe in the main thread.
 	http.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "text/plain")
@@ -108,16 +113,16 @@ func setupAppV2(ctx context.Context, cfg *rest.Config, cluster string) error {
 	if err != nil {
 		return errors.Wrap(err, "create controller manager")
 	}
-	if err := chartassignment.Add(ctx, mgr, cluster, !*omitCopyingPullsecret); err != nil {
+	if err := chartassignment.Add(ctx, mgr, cluster, !*omitCopyingPullsecret, http.DefaultServeMux); err != nil {
 		return errors.Wrap(err, "add ChartAssignment controller")
 	}
 
 	if *webhookEnabled {
 		var webhook *admission.Webhook
 		if *cloudCluster {
-			webhook = chartassignment.NewValidationWebhook(mgr)
+			webhook = chartassignment.NewValidationWebhook(mgr, *webhookRenderCharts)
 		} else {
-			webhook = chartassignment.NewValidationWebhookForEdgeCluster(mgr, cluster)
+			webhook = chartassignment.NewValidationWebhookForEdgeCluster(mgr, cluster, *webhookRenderCharts)
 		}
 		srv := mgr.GetWebhookServer()
 		srv.CertDir = *certDir
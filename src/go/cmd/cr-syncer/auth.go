@@ -0,0 +1,82 @@
+// Copyright 2026 The Cloud Robotics Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+	"k8s.io/client-go/rest"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+// Values of --remote-auth.
+const (
+	authGoogle     = "google"
+	authTokenFile  = "token-file"
+	authClientCert = "client-cert"
+	authExec       = "exec"
+)
+
+// configureRemoteAuth sets the credentials selected by --remote-auth in the
+// config of the remote cluster. Google credentials aren't supported by
+// client-go itself, so it returns a function that wraps the transport to add
+// them.
+func configureRemoteAuth(ctx context.Context, config *rest.Config) (func(http.RoundTripper) http.RoundTripper, error) {
+	config.TLSClientConfig.CAFile = *remoteCAFile
+	noWrap := func(rt http.RoundTripper) http.RoundTripper { return rt }
+
+	switch *remoteAuth {
+	case authGoogle:
+		tokenSource, err := google.DefaultTokenSource(ctx, "https://www.googleapis.com/auth/cloud-platform")
+		if err != nil {
+			return nil, err
+		}
+		return func(rt http.RoundTripper) http.RoundTripper {
+			return &oauth2.Transport{Source: tokenSource, Base: rt}
+		}, nil
+	case authTokenFile:
+		if *remoteTokenFile == "" {
+			return nil, fmt.Errorf("--remote-token-file is required for --remote-auth=%s", authTokenFile)
+		}
+		config.BearerTokenFile = *remoteTokenFile
+		return noWrap, nil
+	case authClientCert:
+		if *remoteCertFile == "" || *remoteKeyFile == "" {
+			return nil, fmt.Errorf("--remote-cert-file and --remote-key-file are required for --remote-auth=%s", authClientCert)
+		}
+		config.TLSClientConfig.CertFile = *remoteCertFile
+		config.TLSClientConfig.KeyFile = *remoteKeyFile
+		return noWrap, nil
+	case authExec:
+		args := strings.Fields(*remoteExecCommand)
+		if len(args) == 0 {
+			return nil, fmt.Errorf("--remote-exec-command is required for --remote-auth=%s", authExec)
+		}
+		config.ExecProvider = &clientcmdapi.ExecConfig{
+			Command:         args[0],
+			Args:            args[1:],
+			APIVersion:      *remoteExecVersion,
+			InteractiveMode: clientcmdapi.NeverExecInteractiveMode,
+		}
+		return noWrap, nil
+	default:
+		return nil, fmt.Errorf("unknown --remote-auth %q", *remoteAuth)
+	}
+}
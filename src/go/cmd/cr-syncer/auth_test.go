@@ -0,0 +1,97 @@
+// Copyright 2026 The Cloud Robotics Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"k8s.io/client-go/rest"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+// setFlag sets a flag value for the duration of a test.
+func setFlag(t *testing.T, f *string, v string) {
+	old := *f
+	*f = v
+	t.Cleanup(func() { *f = old })
+}
+
+func TestConfigureRemoteAuth(t *testing.T) {
+	setFlag(t, remoteCAFile, "/etc/cr-syncer/ca.crt")
+
+	t.Run("token-file", func(t *testing.T) {
+		setFlag(t, remoteAuth, authTokenFile)
+		setFlag(t, remoteTokenFile, "/var/run/secrets/tokens/robot")
+		config := &rest.Config{}
+		if _, err := configureRemoteAuth(context.Background(), config); err != nil {
+			t.Fatal(err)
+		}
+		if config.BearerTokenFile != "/var/run/secrets/tokens/robot" || config.TLSClientConfig.CAFile != "/etc/cr-syncer/ca.crt" {
+			t.Errorf("unexpected config %+v", config)
+		}
+	})
+	t.Run("client-cert", func(t *testing.T) {
+		setFlag(t, remoteAuth, authClientCert)
+		setFlag(t, remoteCertFile, "/etc/cr-syncer/tls.crt")
+		setFlag(t, remoteKeyFile, "/etc/cr-syncer/tls.key")
+		config := &rest.Config{}
+		if _, err := configureRemoteAuth(context.Background(), config); err != nil {
+			t.Fatal(err)
+		}
+		if config.TLSClientConfig.CertFile != "/etc/cr-syncer/tls.crt" || config.TLSClientConfig.KeyFile != "/etc/cr-syncer/tls.key" {
+			t.Errorf("unexpected TLS config %+v", config.TLSClientConfig)
+		}
+	})
+	t.Run("exec", func(t *testing.T) {
+		setFlag(t, remoteAuth, authExec)
+		setFlag(t, remoteExecCommand, "/usr/bin/robot-auth --audience cloud")
+		config := &rest.Config{}
+		if _, err := configureRemoteAuth(context.Background(), config); err != nil {
+			t.Fatal(err)
+		}
+		want := &clientcmdapi.ExecConfig{
+			Command:         "/usr/bin/robot-auth",
+			Args:            []string{"--audience", "cloud"},
+			APIVersion:      *remoteExecVersion,
+			InteractiveMode: clientcmdapi.NeverExecInteractiveMode,
+		}
+		if !reflect.DeepEqual(config.ExecProvider, want) {
+			t.Errorf("unexpected exec config\nwant: %+v\ngot:  %+v", want, config.ExecProvider)
+		}
+	})
+
+	invalid := map[string]func(t *testing.T){
+		"unknown": func(t *testing.T) {
+			setFlag(t, remoteAuth, "password")
+		},
+		"missing-key": func(t *testing.T) {
+			setFlag(t, remoteAuth, authClientCert)
+			setFlag(t, remoteCertFile, "/etc/cr-syncer/tls.crt")
+		},
+		"missing-command": func(t *testing.T) {
+			setFlag(t, remoteAuth, authExec)
+		},
+	}
+	for name, setup := range invalid {
+		t.Run(name, func(t *testing.T) {
+			setup(t)
+			if _, err := configureRemoteAuth(context.Background(), &rest.Config{}); err == nil {
+				t.Error("expected error, got none")
+			}
+		})
+	}
+}
@@ -0,0 +1,128 @@
+// Copyright 2026 The Cloud Robotics Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const (
+	// Annotations attached to CRDs.
+	annotationDeletionPolicy      = "cr-syncer.cloudrobotics.com/deletion-policy"
+	annotationDeletionGracePeriod = "cr-syncer.cloudrobotics.com/deletion-grace-period"
+
+	// Annotations attached to downstream CRs whose upstream resource was
+	// deleted. delete-after is a tombstone with the time at which the
+	// resource is deleted, orphaned the time at which it was orphaned.
+	annotationDeleteAfter = "cr-syncer.cloudrobotics.com/delete-after"
+	annotationOrphaned    = "cr-syncer.cloudrobotics.com/orphaned"
+)
+
+// Values of the deletion-policy annotation.
+const (
+	// deletionImmediate deletes the downstream resource with the upstream
+	// resource.
+	deletionImmediate = "immediate"
+	// deletionDelay deletes the downstream resource after the grace
+	// period, unless the upstream resource is recreated before.
+	deletionDelay = "delay"
+	// deletionOrphan keeps the downstream resource and marks it as
+	// orphaned.
+	deletionOrphan = "orphan"
+)
+
+// parseDeletionPolicy validates a deletion policy and its grace period,
+// which is required for "delay" and must be empty otherwise.
+func parseDeletionPolicy(policy, gracePeriod string) (string, time.Duration, error) {
+	switch policy {
+	case "", deletionImmediate:
+		policy = deletionImmediate
+	case deletionDelay, deletionOrphan:
+	default:
+		return "", 0, fmt.Errorf("unknown deletion policy %q", policy)
+	}
+	if policy != deletionDelay {
+		if gracePeriod != "" {
+			return "", 0, fmt.Errorf("grace period is only supported for deletion policy %q", deletionDelay)
+		}
+		return policy, 0, nil
+	}
+	d, err := time.ParseDuration(gracePeriod)
+	if err != nil || d <= 0 {
+		return "", 0, fmt.Errorf("deletion policy %q requires a positive grace period, got %q", deletionDelay, gracePeriod)
+	}
+	return policy, d, nil
+}
+
+// propagateDeletion handles the deletion of the upstream resource of key
+// according to the deletion policy. dst is the downstream resource.
+//
+// With a grace period, the first call marks dst with a tombstone and later
+// calls delete it once the tombstone expired. syncUpstream() replaces the
+// annotations of dst, which removes the tombstone or orphaned mark if the
+// upstream resource is recreated.
+func (s *crSyncer) propagateDeletion(key string, dst *unstructured.Unstructured) error {
+	switch s.cfg.deletionPolicy {
+	case deletionOrphan:
+		if _, ok := dst.GetAnnotations()[annotationOrphaned]; ok {
+			return nil
+		}
+		setAnnotation(dst, annotationOrphaned, time.Now().UTC().Format(time.RFC3339))
+		if err := s.updateDownstreamMetadata(dst); err != nil {
+			return newAPIErrorf(dst, "marking downstream as orphaned failed: %s", err)
+		}
+		log.Printf("Orphaned %s %s as its upstream resource was deleted", dst.GetKind(), dst.GetName())
+		return nil
+	case deletionDelay:
+		deadline, err := time.Parse(time.RFC3339, dst.GetAnnotations()[annotationDeleteAfter])
+		if err != nil {
+			deadline = time.Now().Add(s.cfg.deletionGracePeriod).UTC().Truncate(time.Second)
+			setAnnotation(dst, annotationDeleteAfter, deadline.Format(time.RFC3339))
+			if err := s.updateDownstreamMetadata(dst); err != nil {
+				return newAPIErrorf(dst, "adding deletion tombstone failed: %s", err)
+			}
+			log.Printf("Deleting %s %s at %s unless its upstream resource is recreated",
+				dst.GetKind(), dst.GetName(), deadline.Format(time.RFC3339))
+		}
+		if remaining := time.Until(deadline); remaining > 0 {
+			s.upstreamQueue.AddAfter(key, remaining)
+			return nil
+		}
+	}
+	if dst.GetDeletionTimestamp() != nil {
+		return nil // Already being deleted.
+	}
+	if err := s.downstream.Delete(s.ctx, dst.GetName(), metav1.DeleteOptions{}); err != nil {
+		if isNotFoundError(err) {
+			return nil
+		}
+		return newAPIErrorf(dst, "downstream delete failed: %s", err)
+	}
+	return nil
+}
+
+// updateDownstreamMetadata writes the annotations of dst to the downstream
+// cluster. A resource that was deleted in the meantime is ignored.
+func (s *crSyncer) updateDownstreamMetadata(dst *unstructured.Unstructured) error {
+	if _, err := s.downstream.Update(s.ctx, dst, metav1.UpdateOptions{}); err != nil && !isNotFoundError(err) {
+		return err
+	}
+	return nil
+}
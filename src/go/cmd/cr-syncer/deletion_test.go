@@ -0,0 +1,213 @@
+// Copyright 2026 The Cloud Robotics Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	crdtypes "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	k8stest "k8s.io/client-go/testing"
+)
+
+func TestParseDeletionPolicy(t *testing.T) {
+	tests := []struct {
+		policy, gracePeriod string
+		want                string
+		wantGracePeriod     time.Duration
+		wantErr             bool
+	}{
+		{"", "", deletionImmediate, 0, false},
+		{"immediate", "", deletionImmediate, 0, false},
+		{"orphan", "", deletionOrphan, 0, false},
+		{"delay", "90s", deletionDelay, 90 * time.Second, false},
+		{"delay", "", "", 0, true},
+		{"delay", "-1m", "", 0, true},
+		{"orphan", "10m", "", 0, true},
+		{"never", "", "", 0, true},
+	}
+	for _, tc := range tests {
+		got, gotGracePeriod, err := parseDeletionPolicy(tc.policy, tc.gracePeriod)
+		if (err != nil) != tc.wantErr {
+			t.Errorf("parseDeletionPolicy(%q, %q) returned error %v, want error: %v", tc.policy, tc.gracePeriod, err, tc.wantErr)
+			continue
+		}
+		if got != tc.want || gotGracePeriod != tc.wantGracePeriod {
+			t.Errorf("parseDeletionPolicy(%q, %q) = %q, %s, want %q, %s", tc.policy, tc.gracePeriod, got, gotGracePeriod, tc.want, tc.wantGracePeriod)
+		}
+	}
+}
+
+// updatedAnnotation returns the annotation of the object written by the only
+// write action, which must be an update.
+func updatedAnnotation(t *testing.T, actions []k8stest.Action, key string) string {
+	t.Helper()
+	writes := filterReadActions(actions)
+	if len(writes) != 1 {
+		t.Fatalf("expected a single write, got %d", len(writes))
+	}
+	update, ok := writes[0].(k8stest.UpdateActionImpl)
+	if !ok {
+		t.Fatalf("expected an update, got %s", sprintAction(writes[0]))
+	}
+	return update.Object.(*unstructured.Unstructured).GetAnnotations()[key]
+}
+
+func TestSyncUpstream_orphan(t *testing.T) {
+	crd := testCRD(crdtypes.NamespaceScoped)
+	crd.Annotations[annotationDeletionPolicy] = deletionOrphan
+	f := newFixture(t)
+
+	f.addLocalObjects(newTestCR("resource1", "spec1", "status1"))
+
+	crs, _ := f.newCRSyncer(crd, "")
+	defer crs.stop()
+	// The informer keeps journaling the updates of the orphaned resource
+	// in the background, so cleanup errors are ignored.
+	dir, err := os.MkdirTemp("", "journal")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	crs.journal = newStatusJournal(dir, 10)
+	if err := crs.journal.record("default/resource1", newTestStatus("1", "status1")); err != nil {
+		t.Fatal(err)
+	}
+
+	crs.startInformers()
+	if err := crs.syncUpstream("default/resource1"); err != nil {
+		t.Fatal(err)
+	}
+
+	orphaned := updatedAnnotation(t, f.local.Actions(), annotationOrphaned)
+	if _, err := time.Parse(time.RFC3339, orphaned); err != nil {
+		t.Errorf("expected orphaned annotation with timestamp, got %q", orphaned)
+	}
+	// The orphaned resource still exists and keeps its journal.
+	if got := pendingStatuses(t, crs.journal, "default/resource1"); len(got) == 0 {
+		t.Errorf("expected journal to be kept, got %v", got)
+	}
+}
+
+func TestSyncUpstream_removesJournalOfDeletedResource(t *testing.T) {
+	crd := testCRD(crdtypes.NamespaceScoped)
+	f := newFixture(t)
+
+	crs, _ := f.newCRSyncer(crd, "")
+	defer crs.stop()
+	crs.journal = newStatusJournal(t.TempDir(), 10)
+	if err := crs.journal.record("default/resource1", newTestStatus("1", "status1")); err != nil {
+		t.Fatal(err)
+	}
+
+	crs.startInformers()
+	if err := crs.syncUpstream("default/resource1"); err != nil {
+		t.Fatal(err)
+	}
+	if got := pendingStatuses(t, crs.journal, "default/resource1"); len(got) != 0 {
+		t.Errorf("expected journal to be removed, got %v", got)
+	}
+}
+
+func TestSyncDownstream_delay(t *testing.T) {
+	crd := testCRD(crdtypes.NamespaceScoped)
+	crd.Annotations[annotationDeletionPolicy] = deletionDelay
+	crd.Annotations[annotationDeletionGracePeriod] = "1h"
+	f := newFixture(t)
+
+	f.addLocalObjects(newTestCR("resource1", "spec1", "status1"))
+
+	crs, _ := f.newCRSyncer(crd, "")
+	defer crs.stop()
+
+	crs.startInformers()
+	if err := crs.syncDownstream("default/resource1"); err != nil {
+		t.Fatal(err)
+	}
+
+	deleteAfter, err := time.Parse(time.RFC3339, updatedAnnotation(t, f.local.Actions(), annotationDeleteAfter))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := time.Until(deleteAfter); d < 59*time.Minute || d > time.Hour {
+		t.Errorf("expected deletion in 1h, got %s", d)
+	}
+}
+
+func TestSyncUpstream_delayExpired(t *testing.T) {
+	crd := testCRD(crdtypes.NamespaceScoped)
+	crd.Annotations[annotationDeletionPolicy] = deletionDelay
+	crd.Annotations[annotationDeletionGracePeriod] = "1h"
+
+	for _, tc := range []struct {
+		desc        string
+		deleteAfter time.Time
+		wantDelete  bool
+	}{
+		{"pending", time.Now().Add(30 * time.Minute), false},
+		{"expired", time.Now().Add(-time.Minute), true},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			f := newFixture(t)
+			tcrLocal := newTestCR("resource1", "spec1", "status1")
+			tcrLocal.SetAnnotations(map[string]string{
+				annotationDeleteAfter: tc.deleteAfter.UTC().Format(time.RFC3339),
+			})
+			f.addLocalObjects(tcrLocal)
+
+			crs, gvr := f.newCRSyncer(crd, "")
+			defer crs.stop()
+
+			crs.startInformers()
+			if err := crs.syncUpstream("default/resource1"); err != nil {
+				t.Fatal(err)
+			}
+
+			if tc.wantDelete {
+				f.expectLocalActions(k8stest.NewDeleteAction(gvr, "default", "resource1"))
+			}
+			f.verifyWriteActions()
+		})
+	}
+}
+
+func TestSyncUpstream_recreatedRemovesMarks(t *testing.T) {
+	crd := testCRD(crdtypes.NamespaceScoped)
+	crd.Annotations[annotationDeletionPolicy] = deletionOrphan
+	f := newFixture(t)
+
+	var (
+		tcrLocal  = newTestCR("resource1", "spec1", "status1")
+		tcrRemote = newTestCR("resource1", "spec2", "status1")
+	)
+	tcrLocal.SetAnnotations(map[string]string{
+		annotationOrphaned: "2026-01-01T00:00:00Z",
+	})
+	f.addLocalObjects(tcrLocal)
+	f.addRemoteObjects(tcrRemote)
+
+	crs, gvr := f.newCRSyncer(crd, "")
+	defer crs.stop()
+
+	crs.startInformers()
+	if err := crs.syncUpstream("default/resource1"); err != nil {
+		t.Fatal(err)
+	}
+
+	f.expectLocalActions(k8stest.NewUpdateAction(gvr, "default", newTestCR("resource1", "spec2", "status1")))
+	f.verifyWriteActions()
+}
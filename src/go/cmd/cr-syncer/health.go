@@ -2,8 +2,12 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"log"
 	"net/http"
+	"strings"
+	"sync"
+	"time"
 
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -36,9 +40,163 @@ func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	//
 	// If this becomes a problem, we could do the requests in the
 	// background and just check the status of the latest request here.
-	if _, err := h.client.Resource(gvr).List(h.ctx, metav1.ListOptions{}); k8serrors.IsUnauthorized(err) {
+	if err := checkRemoteAuth(h.ctx, h.client); err != nil {
 		log.Printf("failed health check: %v", err)
 		http.Error(w, "unhealthy", http.StatusInternalServerError)
 		return
 	}
 }
+
+// checkRemoteAuth returns an error if the remote cluster rejects the
+// credentials. Other errors are ignored, as they may be caused by transient
+// network issues that a restart doesn't fix.
+func checkRemoteAuth(ctx context.Context, client dynamic.Interface) error {
+	if _, err := client.Resource(gvr).List(ctx, metav1.ListOptions{}); k8serrors.IsUnauthorized(err) {
+		return err
+	}
+	return nil
+}
+
+// loopStallTimeout is the time without a heartbeat of the main loop after
+// which /healthz fails.
+const loopStallTimeout = time.Minute
+
+// heartbeat records when a loop last ran.
+type heartbeat struct {
+	mu   sync.Mutex
+	last time.Time
+}
+
+func newHeartbeat() *heartbeat {
+	return &heartbeat{last: time.Now()}
+}
+
+func (h *heartbeat) beat() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.last = time.Now()
+}
+
+func (h *heartbeat) since() time.Duration {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return time.Since(h.last)
+}
+
+// livenessHandler serves /healthz. It only checks the health of the
+// cr-syncer itself, so that it isn't restarted while the remote cluster is
+// unreachable: the main loop must be alive and the remote cluster must not
+// reject the credentials, which a restart may renew.
+type livenessHandler struct {
+	ctx    context.Context
+	client dynamic.Interface
+	loop   *heartbeat
+}
+
+func newLivenessHandler(ctx context.Context, client dynamic.Interface, loop *heartbeat) http.Handler {
+	return &livenessHandler{ctx: ctx, client: client, loop: loop}
+}
+
+func (h *livenessHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var out strings.Builder
+	failed := false
+	check := func(name string, err error) {
+		if err != nil {
+			failed = true
+			fmt.Fprintf(&out, "[-]%s failed: %s\n", name, err)
+		} else {
+			fmt.Fprintf(&out, "[+]%s ok\n", name)
+		}
+	}
+	var loopErr error
+	if d := h.loop.since(); d > loopStallTimeout {
+		loopErr = fmt.Errorf("no heartbeat for %s", d.Round(time.Second))
+	}
+	check("main-loop", loopErr)
+	check("remote-auth", checkRemoteAuth(h.ctx, h.client))
+	if failed {
+		log.Printf("failed %s check:\n%s", r.URL.Path, out.String())
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	fmt.Fprint(w, out.String())
+}
+
+// remoteContacts records when the informers last listed or watched the
+// remote cluster successfully, by informer key.
+var remoteContacts = &contactTracker{last: map[informerKey]time.Time{}}
+
+type contactTracker struct {
+	mu   sync.Mutex
+	last map[informerKey]time.Time
+}
+
+func (c *contactTracker) record(key informerKey) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.last[key] = time.Now()
+}
+
+func (c *contactTracker) get(key informerKey) time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.last[key]
+}
+
+// syncerStatus is the state of a syncer that is reported by /readyz.
+type syncerStatus struct {
+	name string
+	// synced is true once the informers listed all resources.
+	synced bool
+	// remoteSilence is the time since the remote cluster was listed or
+	// watched successfully, or since the syncer started.
+	remoteSilence     time.Duration
+	queueDepth        int
+	consecutiveErrors int
+}
+
+// problems returns the failed readiness checks.
+func (st syncerStatus) problems() []string {
+	var res []string
+	if *maxRemoteSilence > 0 && st.remoteSilence > *maxRemoteSilence {
+		res = append(res, fmt.Sprintf("no response from remote cluster for %s", st.remoteSilence.Round(time.Second)))
+	}
+	if *maxConsecutiveErrors > 0 && st.consecutiveErrors >= *maxConsecutiveErrors {
+		res = append(res, fmt.Sprintf("%d consecutive sync errors", st.consecutiveErrors))
+	}
+	if !st.synced {
+		res = append(res, "informers not synced")
+	}
+	if *maxQueueDepth > 0 && st.queueDepth > *maxQueueDepth {
+		res = append(res, fmt.Sprintf("%d queued resources", st.queueDepth))
+	}
+	return res
+}
+
+// readinessHandler serves /readyz with the status of all syncers. The
+// response lists the syncers with "[+]" if they passed the checks or "[-]"
+// and the problems otherwise.
+type readinessHandler struct {
+	syncers *syncerSet
+}
+
+func newReadinessHandler(syncers *syncerSet) http.Handler {
+	return &readinessHandler{syncers: syncers}
+}
+
+func (h *readinessHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var out strings.Builder
+	failed := false
+	for _, st := range h.syncers.statuses() {
+		if problems := st.problems(); len(problems) > 0 {
+			failed = true
+			fmt.Fprintf(&out, "[-]%s failed: %s\n", st.name, strings.Join(problems, ", "))
+		} else {
+			fmt.Fprintf(&out, "[+]%s ok\n", st.name)
+		}
+	}
+	if failed {
+		log.Printf("failed %s check:\n%s", r.URL.Path, out.String())
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	fmt.Fprint(w, out.String())
+}
@@ -2,10 +2,14 @@ package main
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
+	crdtypes "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -78,3 +82,97 @@ func TestUnhealthy(t *testing.T) {
 		t.Errorf("GET / returned status %d, want %d", res.StatusCode, http.StatusInternalServerError)
 	}
 }
+
+func TestSyncerStatusProblems(t *testing.T) {
+	tests := []struct {
+		desc  string
+		st    syncerStatus
+		ready bool
+	}{
+		{"ok", syncerStatus{synced: true, remoteSilence: time.Minute, queueDepth: 10}, true},
+		{"not synced", syncerStatus{synced: false}, false},
+		{"long queue", syncerStatus{synced: true, queueDepth: 1001}, false},
+		{"remote silent", syncerStatus{synced: true, remoteSilence: time.Hour}, false},
+		{"failing", syncerStatus{synced: true, consecutiveErrors: 50}, false},
+	}
+	for _, tc := range tests {
+		if got := len(tc.st.problems()) == 0; got != tc.ready {
+			t.Errorf("%s: ready = %v, want %v", tc.desc, got, tc.ready)
+		}
+	}
+}
+
+func serveCheck(h http.Handler) (int, string) {
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+	return w.Code, w.Body.String()
+}
+
+func TestLivenessHandler(t *testing.T) {
+	tests := []struct {
+		desc     string
+		err      error
+		stalled  bool
+		wantCode int
+		wantBody string
+	}{
+		{"ok", nil, false, http.StatusOK, "[+]remote-auth ok"},
+		// Remote errors other than Unauthorized, eg while the remote
+		// cluster is unreachable, aren't fixed by a restart.
+		{"unreachable", k8serrors.NewServiceUnavailable(""), false, http.StatusOK, "[+]remote-auth ok"},
+		{"unauthorized", k8serrors.NewUnauthorized(""), false, http.StatusServiceUnavailable, "[-]remote-auth failed"},
+		{"stalled", nil, true, http.StatusServiceUnavailable, "[-]main-loop failed"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			client := fake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+				map[schema.GroupVersionResource]string{
+					gvr: "RobotList",
+				},
+			)
+			if tc.err != nil {
+				client.PrependReactor("*", "*", func(k8stest.Action) (bool, runtime.Object, error) {
+					return true, nil, tc.err
+				})
+			}
+			loop := newHeartbeat()
+			if tc.stalled {
+				loop.last = time.Now().Add(-2 * loopStallTimeout)
+			}
+			code, body := serveCheck(newLivenessHandler(context.Background(), client, loop))
+			if code != tc.wantCode || !strings.Contains(body, tc.wantBody) {
+				t.Errorf("/healthz returned %d %q, want %d with %q", code, body, tc.wantCode, tc.wantBody)
+			}
+		})
+	}
+}
+
+func TestReadinessHandler(t *testing.T) {
+	crd := testCRD(crdtypes.NamespaceScoped)
+	f := newFixture(t)
+	crs, _ := f.newCRSyncer(crd, "")
+	defer crs.stop()
+	ss := &syncerSet{syncers: map[string][]*crSyncer{"foos": {crs}}}
+	h := newReadinessHandler(ss)
+
+	// The informers haven't been started yet.
+	if code, body := serveCheck(h); code != http.StatusServiceUnavailable || !strings.Contains(body, "informers not synced") {
+		t.Errorf("/readyz returned status %d, want %d: %s", code, http.StatusServiceUnavailable, body)
+	}
+
+	crs.startInformers()
+	if code, body := serveCheck(h); code != http.StatusOK {
+		t.Errorf("/readyz returned status %d after sync, want %d: %s", code, http.StatusOK, body)
+	}
+
+	for i := 0; i < *maxConsecutiveErrors; i++ {
+		crs.health.recordSync("upstream", errors.New("conflict"))
+	}
+	if code, body := serveCheck(h); code != http.StatusServiceUnavailable || !strings.Contains(body, "consecutive sync errors") {
+		t.Errorf("/readyz returned status %d with failing syncs, want %d: %s", code, http.StatusServiceUnavailable, body)
+	}
+	crs.health.recordSync("upstream", nil)
+	if code, body := serveCheck(h); code != http.StatusOK {
+		t.Errorf("/readyz returned status %d after successful sync, want %d: %s", code, http.StatusOK, body)
+	}
+}
@@ -0,0 +1,219 @@
+// Copyright 2026 The Cloud Robotics Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.opencensus.io/stats"
+	"go.opencensus.io/tag"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/cache"
+)
+
+// informerIdleTimeout is how long informers are kept running after the
+// last syncer released them, so that a restarted syncer can reuse them.
+const informerIdleTimeout = time.Minute
+
+// informerKey identifies the resources an informer lists and watches.
+type informerKey struct {
+	cluster       string // "local" or "remote".
+	gvr           schema.GroupVersionResource
+	namespace     string
+	labelSelector string
+	fieldSelector string
+}
+
+// sharedInformer is a running informer along with the number of syncers
+// using it. Handlers of an informer can't be removed, so the sharedInformer
+// is registered as its only handler and dispatches events to the handlers
+// of the syncers currently subscribed.
+type sharedInformer struct {
+	key  informerKey
+	inf  cache.SharedIndexInformer
+	done chan struct{}
+	refs int
+	// gen counts the releases, so that the idle timer of an earlier
+	// release doesn't stop an informer that was acquired since.
+	gen int
+
+	mu       sync.RWMutex
+	handlers map[int]cache.ResourceEventHandler
+	nextID   int
+}
+
+func newSharedInformer(key informerKey, inf cache.SharedIndexInformer) *sharedInformer {
+	si := &sharedInformer{
+		key:      key,
+		inf:      inf,
+		done:     make(chan struct{}),
+		refs:     1,
+		handlers: map[int]cache.ResourceEventHandler{},
+	}
+	inf.AddEventHandler(si)
+	return si
+}
+
+// subscribe adds a handler for the events of the informer. Like handlers
+// added to a running informer, it receives an add event for every cached
+// object. The returned func unsubscribes the handler.
+func (si *sharedInformer) subscribe(h cache.ResourceEventHandler) func() {
+	si.mu.Lock()
+	id := si.nextID
+	si.nextID++
+	si.handlers[id] = h
+	si.mu.Unlock()
+
+	// Objects that are also delivered by the informer in the meantime are
+	// queued twice, which is harmless.
+	for _, obj := range si.inf.GetStore().List() {
+		h.OnAdd(obj)
+	}
+	return func() {
+		si.mu.Lock()
+		defer si.mu.Unlock()
+		delete(si.handlers, id)
+	}
+}
+
+func (si *sharedInformer) OnAdd(obj interface{}) {
+	si.mu.RLock()
+	defer si.mu.RUnlock()
+	for _, h := range si.handlers {
+		h.OnAdd(obj)
+	}
+}
+
+func (si *sharedInformer) OnUpdate(oldObj, newObj interface{}) {
+	si.mu.RLock()
+	defer si.mu.RUnlock()
+	for _, h := range si.handlers {
+		h.OnUpdate(oldObj, newObj)
+	}
+}
+
+func (si *sharedInformer) OnDelete(obj interface{}) {
+	si.mu.RLock()
+	defer si.mu.RUnlock()
+	for _, h := range si.handlers {
+		h.OnDelete(obj)
+	}
+}
+
+func (si *sharedInformer) stop() {
+	select {
+	case <-si.done:
+	default:
+		close(si.done)
+	}
+}
+
+// informerCache shares informers between syncers, so that a syncer that is
+// restarted with a new config doesn't relist resources that are already
+// cached. Informers are stopped once they weren't used for
+// informerIdleTimeout.
+//
+// Individual informers of a dynamic shared informer factory can't be
+// stopped, which is needed to restart informers on conflict errors and to
+// stop watching resources that are no longer synced.
+type informerCache struct {
+	mu        sync.Mutex
+	informers map[informerKey]*sharedInformer
+	// started records keys that had an informer before, to count
+	// restarts.
+	started map[informerKey]bool
+}
+
+func newInformerCache() *informerCache {
+	return &informerCache{
+		informers: map[informerKey]*sharedInformer{},
+		started:   map[informerKey]bool{},
+	}
+}
+
+// acquire returns a running informer for the key. It is created with newInf
+// if there is none yet or if fresh is set, in which case the existing
+// informer is left to its current users.
+func (c *informerCache) acquire(key informerKey, newInf func() cache.SharedIndexInformer, fresh bool) *sharedInformer {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if si, ok := c.informers[key]; ok {
+		if !fresh {
+			si.refs++
+			return si
+		}
+		if si.refs == 0 {
+			si.stop()
+		}
+	}
+	if c.started[key] {
+		recordInformerRestart(key)
+	}
+	c.started[key] = true
+	si := newSharedInformer(key, newInf())
+	c.informers[key] = si
+	go si.inf.Run(si.done)
+	return si
+}
+
+// release stops the informer after informerIdleTimeout unless it's
+// acquired again.
+func (c *informerCache) release(si *sharedInformer) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	si.refs--
+	if si.refs > 0 {
+		return
+	}
+	if c.informers[si.key] != si {
+		// Replaced by a fresh informer.
+		si.stop()
+		return
+	}
+	si.gen++
+	gen := si.gen
+	time.AfterFunc(informerIdleTimeout, func() {
+		c.stopIdle(si, gen)
+	})
+}
+
+// stopIdle stops the informer unless it was acquired or released again
+// since the release of generation gen.
+func (c *informerCache) stopIdle(si *sharedInformer, gen int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if si.refs > 0 || si.gen != gen {
+		return
+	}
+	si.stop()
+	if c.informers[si.key] == si {
+		delete(c.informers, si.key)
+	}
+}
+
+func recordInformerRestart(key informerKey) {
+	ctx, err := tag.New(context.Background(),
+		tag.Insert(tagResource, key.gvr.GroupResource().String()),
+		tag.Insert(tagCluster, key.cluster),
+	)
+	if err != nil {
+		panic(err)
+	}
+	stats.Record(ctx, mInformerRestarts.M(1))
+}
@@ -0,0 +1,183 @@
+// Copyright 2026 The Cloud Robotics Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	crdtypes "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	k8sfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/tools/cache"
+)
+
+func newStubInformer() cache.SharedIndexInformer {
+	return cache.NewSharedIndexInformer(
+		&cache.ListWatch{
+			ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+				return &unstructured.UnstructuredList{}, nil
+			},
+			WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+				return watch.NewFake(), nil
+			},
+		},
+		&unstructured.Unstructured{},
+		0,
+		nil,
+	)
+}
+
+func TestInformerCache(t *testing.T) {
+	c := newInformerCache()
+	key := informerKey{cluster: "remote", gvr: schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}}
+
+	a := c.acquire(key, newStubInformer, false)
+	b := c.acquire(key, newStubInformer, false)
+	if a != b {
+		t.Error("expected informer to be shared")
+	}
+	c.release(a)
+	c.release(b)
+	// Released informers are kept for a while to be reused.
+	d := c.acquire(key, newStubInformer, false)
+	if d != a {
+		t.Error("expected released informer to be reused")
+	}
+	e := c.acquire(key, newStubInformer, true)
+	if e == d {
+		t.Error("expected fresh informer")
+	}
+	c.release(d)
+	select {
+	case <-d.done:
+	default:
+		t.Error("expected replaced informer to be stopped once released")
+	}
+	c.release(e)
+}
+
+func TestInformerCache_staleIdleTimerKeepsInformer(t *testing.T) {
+	c := newInformerCache()
+	key := informerKey{cluster: "remote", gvr: schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}}
+
+	a := c.acquire(key, newStubInformer, false)
+	c.release(a)
+	staleGen := a.gen
+	if b := c.acquire(key, newStubInformer, false); b != a {
+		t.Fatal("expected released informer to be reused")
+	}
+	c.release(a)
+
+	// The timer of the first release fires after the second release.
+	c.stopIdle(a, staleGen)
+	select {
+	case <-a.done:
+		t.Fatal("expected informer to keep running until its last release is idle")
+	default:
+	}
+	c.stopIdle(a, a.gen)
+	select {
+	case <-a.done:
+	default:
+		t.Error("expected idle informer to be stopped")
+	}
+	if _, ok := c.informers[key]; ok {
+		t.Error("expected idle informer to be removed")
+	}
+}
+
+func TestSharedInformer_dispatchesToSubscribers(t *testing.T) {
+	key := informerKey{cluster: "remote", gvr: schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}}
+	si := newSharedInformer(key, newStubInformer())
+	cached := &unstructured.Unstructured{}
+	cached.SetName("cached")
+	if err := si.inf.GetStore().Add(cached); err != nil {
+		t.Fatal(err)
+	}
+
+	var first, second []string
+	record := func(events *[]string) cache.ResourceEventHandler {
+		return cache.ResourceEventHandlerFuncs{
+			AddFunc: func(obj interface{}) {
+				*events = append(*events, "add "+obj.(*unstructured.Unstructured).GetName())
+			},
+			DeleteFunc: func(obj interface{}) {
+				*events = append(*events, "delete "+obj.(*unstructured.Unstructured).GetName())
+			},
+		}
+	}
+	unsubscribe := si.subscribe(record(&first))
+	if want := []string{"add cached"}; fmt.Sprint(first) != fmt.Sprint(want) {
+		t.Errorf("expected cached objects to be replayed, got %v", first)
+	}
+
+	// A restarted syncer replaces its handler instead of adding one.
+	unsubscribe()
+	si.subscribe(record(&second))
+	si.OnDelete(cached)
+	if len(si.handlers) != 1 {
+		t.Errorf("expected one handler, got %d", len(si.handlers))
+	}
+	if want := []string{"add cached"}; fmt.Sprint(first) != fmt.Sprint(want) {
+		t.Errorf("expected no events after unsubscribing, got %v", first)
+	}
+	if want := []string{"add cached", "delete cached"}; fmt.Sprint(second) != fmt.Sprint(want) {
+		t.Errorf("got events %v, want %v", second, want)
+	}
+}
+
+func TestSyncerSet_restartsOnlyOnConfigChange(t *testing.T) {
+	crd := testCRD(crdtypes.NamespaceScoped)
+	gvr := schema.GroupVersionResource{Group: "crds.example.com", Version: "v1", Resource: "goals"}
+	sc := runtime.NewScheme()
+	sc.AddKnownTypeWithName(schema.GroupVersionKind{Group: gvr.Group, Version: gvr.Version, Kind: "Goal"}, &unstructured.Unstructured{})
+	listKinds := map[schema.GroupVersionResource]string{gvr: fmt.Sprintf("%sList", "Goal")}
+	local := k8sfake.NewSimpleDynamicClientWithCustomListKinds(sc, listKinds)
+	remote := k8sfake.NewSimpleDynamicClientWithCustomListKinds(sc, listKinds)
+
+	ss := newSyncerSet(context.Background(), local, remote, "")
+	ss.handleCRD(CrdChange{Type: watch.Added, CRD: &crd})
+	running := ss.syncers["goals.crds.example.com"]
+	if len(running) != 1 {
+		t.Fatalf("expected one syncer, got %d", len(running))
+	}
+
+	// A status update of the CRD doesn't restart the syncer.
+	updated := crd.DeepCopy()
+	updated.ResourceVersion = "2"
+	updated.Status.AcceptedNames.Kind = "Goal"
+	ss.handleCRD(CrdChange{Type: watch.Modified, CRD: updated})
+	if got := ss.syncers["goals.crds.example.com"]; len(got) != 1 || got[0] != running[0] {
+		t.Error("expected syncer to keep running")
+	}
+
+	updated = updated.DeepCopy()
+	updated.Annotations[annotationStatusSubtree] = "robot"
+	ss.handleCRD(CrdChange{Type: watch.Modified, CRD: updated})
+	if got := ss.syncers["goals.crds.example.com"]; len(got) != 1 || got[0] == running[0] {
+		t.Error("expected syncer to be restarted")
+	}
+
+	ss.handleCRD(CrdChange{Type: watch.Deleted, CRD: updated})
+	if len(ss.syncers) != 0 {
+		t.Errorf("expected no syncers, got %v", ss.syncers)
+	}
+}
@@ -0,0 +1,227 @@
+// Copyright 2026 The Cloud Robotics Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	utiljson "k8s.io/apimachinery/pkg/util/json"
+)
+
+// Annotation attached to CRDs to enable the status journal with the given
+// maximum number of entries per resource.
+const annotationStatusJournal = "cr-syncer.cloudrobotics.com/status-journal"
+
+// chownJournalDir creates the journal directory and hands it over to owner,
+// given as "<uid>:<gid>". Host directories are created as root, while the
+// cr-syncer runs as an unprivileged user.
+func chownJournalDir(dir, owner string) error {
+	parts := strings.Split(owner, ":")
+	if len(parts) != 2 {
+		return fmt.Errorf("owner %q isn't <uid>:<gid>", owner)
+	}
+	uid, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return fmt.Errorf("invalid uid in owner %q: %v", owner, err)
+	}
+	gid, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return fmt.Errorf("invalid gid in owner %q: %v", owner, err)
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+	return os.Chown(dir, uid, gid)
+}
+
+// statusJournal records every status change of resources in the local
+// cluster on disk, so that the transitions are replayed in order to the
+// cloud once it is reachable, instead of only the latest status. There is
+// one file per resource. Once a resource has more than limit unsynced
+// status changes, the oldest are dropped.
+type statusJournal struct {
+	dir   string
+	limit int
+
+	mu sync.Mutex
+}
+
+// journalEntry is a recorded status change.
+type journalEntry struct {
+	Seq             int64           `json:"seq"`
+	ResourceVersion string          `json:"resourceVersion"`
+	Status          json.RawMessage `json:"status"`
+}
+
+// journalFile is the content of a journal file.
+type journalFile struct {
+	// Entries are the status changes that weren't synced yet.
+	Entries []journalEntry `json:"entries,omitempty"`
+	// Last is the last recorded status, to skip events that didn't
+	// change the status.
+	Last json.RawMessage `json:"last,omitempty"`
+	// NextSeq is the sequence number of the next entry.
+	NextSeq int64 `json:"nextSeq"`
+	// Dropped counts the entries that were dropped since the last replay
+	// as the journal was full.
+	Dropped int64 `json:"dropped,omitempty"`
+}
+
+func newStatusJournal(dir string, limit int) *statusJournal {
+	return &statusJournal{dir: dir, limit: limit}
+}
+
+// path returns the journal file of the resource with the given queue key.
+// Names and namespaces can't contain underscores, so they are used to
+// separate them.
+func (j *statusJournal) path(key string) string {
+	return filepath.Join(j.dir, strings.Replace(key, "/", "_", 1)+".json")
+}
+
+func (j *statusJournal) read(key string) (*journalFile, error) {
+	data, err := ioutil.ReadFile(j.path(key))
+	if os.IsNotExist(err) {
+		return &journalFile{}, nil
+	} else if err != nil {
+		return nil, err
+	}
+	f := &journalFile{}
+	if err := json.Unmarshal(data, f); err != nil {
+		return nil, fmt.Errorf("invalid journal %s: %s", j.path(key), err)
+	}
+	return f, nil
+}
+
+// write replaces the journal file atomically, so that it isn't corrupted
+// if the robot loses power.
+func (j *statusJournal) write(key string, f *journalFile) error {
+	data, err := json.Marshal(f)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(j.dir, 0700); err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempFile(j.dir, ".tmp-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), j.path(key))
+}
+
+// record adds the status of obj to the journal if it changed.
+func (j *statusJournal) record(key string, obj *unstructured.Unstructured) error {
+	status, err := json.Marshal(obj.Object["status"])
+	if err != nil {
+		return err
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	f, err := j.read(key)
+	if err != nil {
+		return err
+	}
+	if bytes.Equal(f.Last, status) {
+		return nil
+	}
+	f.Entries = append(f.Entries, journalEntry{
+		Seq:             f.NextSeq,
+		ResourceVersion: obj.GetResourceVersion(),
+		Status:          status,
+	})
+	f.Last = status
+	f.NextSeq++
+	if n := len(f.Entries) - j.limit; n > 0 {
+		f.Entries = f.Entries[n:]
+		f.Dropped += int64(n)
+	}
+	return j.write(key, f)
+}
+
+// pending returns the status changes that weren't synced yet and the number
+// of changes that were dropped before them.
+func (j *statusJournal) pending(key string) ([]journalEntry, int64, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	f, err := j.read(key)
+	if err != nil {
+		return nil, 0, err
+	}
+	return f.Entries, f.Dropped, nil
+}
+
+// ack removes the entries up to and including seq after they were synced.
+func (j *statusJournal) ack(key string, seq int64) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	f, err := j.read(key)
+	if err != nil {
+		return err
+	}
+	i := 0
+	for i < len(f.Entries) && f.Entries[i].Seq <= seq {
+		i++
+	}
+	if i == 0 && f.Dropped == 0 {
+		return nil
+	}
+	f.Entries = f.Entries[i:]
+	f.Dropped = 0
+	return j.write(key, f)
+}
+
+// remove deletes the journal of a deleted resource.
+func (j *statusJournal) remove(key string) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if err := os.Remove(j.path(key)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// status decodes the recorded status.
+func (e journalEntry) status() (interface{}, error) {
+	var status interface{}
+	if err := utiljson.Unmarshal(e.Status, &status); err != nil {
+		return nil, err
+	}
+	return status, nil
+}
@@ -0,0 +1,161 @@
+// Copyright 2026 The Cloud Robotics Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"testing"
+
+	crdtypes "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	k8stest "k8s.io/client-go/testing"
+)
+
+func newTestStatus(rv string, status interface{}) *unstructured.Unstructured {
+	o := newTestCR("resource1", "spec1", status)
+	o.SetResourceVersion(rv)
+	return o
+}
+
+func pendingStatuses(t *testing.T, j *statusJournal, key string) []interface{} {
+	t.Helper()
+	entries, _, err := j.pending(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var res []interface{}
+	for _, e := range entries {
+		status, err := e.status()
+		if err != nil {
+			t.Fatal(err)
+		}
+		res = append(res, status)
+	}
+	return res
+}
+
+func TestStatusJournal(t *testing.T) {
+	dir := t.TempDir()
+	key := "default/resource1"
+	j := newStatusJournal(dir, 3)
+
+	for i, status := range []interface{}{
+		map[string]interface{}{"phase": "a", "progress": int64(1)},
+		map[string]interface{}{"phase": "a", "progress": int64(1)}, // Unchanged.
+		"b", "c", "d",
+	} {
+		if err := j.record(key, newTestStatus(strconv.Itoa(i+1), status)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The journal is persisted and bounded.
+	j = newStatusJournal(dir, 3)
+	entries, dropped, err := j.pending(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dropped != 1 {
+		t.Errorf("expected 1 dropped entry, got %d", dropped)
+	}
+	if got, want := pendingStatuses(t, j, key), []interface{}{"b", "c", "d"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected pending statuses\nwant: %v\ngot:  %v", want, got)
+	}
+
+	if err := j.ack(key, entries[1].Seq); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := pendingStatuses(t, j, key), []interface{}{"d"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected pending statuses after ack\nwant: %v\ngot:  %v", want, got)
+	}
+	// The last status is still known after it was synced.
+	if err := j.record(key, newTestStatus("6", "d")); err != nil {
+		t.Fatal(err)
+	}
+	if got := pendingStatuses(t, j, key); len(got) != 1 {
+		t.Errorf("expected unchanged status to be skipped, got %v", got)
+	}
+
+	if err := j.remove(key); err != nil {
+		t.Fatal(err)
+	}
+	if got := pendingStatuses(t, j, key); len(got) != 0 {
+		t.Errorf("expected empty journal after removal, got %v", got)
+	}
+}
+
+func TestChownJournalDir(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "journal")
+	owner := fmt.Sprintf("%d:%d", os.Getuid(), os.Getgid())
+	if err := chownJournalDir(dir, owner); err != nil {
+		t.Fatal(err)
+	}
+	if fi, err := os.Stat(dir); err != nil || !fi.IsDir() {
+		t.Errorf("expected journal dir to be created, got %v", err)
+	}
+	for _, owner := range []string{"", "65532", "a:1", "1:b"} {
+		if err := chownJournalDir(dir, owner); err == nil {
+			t.Errorf("expected error for owner %q", owner)
+		}
+	}
+}
+
+func TestSyncDownstream_replaysJournal(t *testing.T) {
+	crd := testCRD(crdtypes.NamespaceScoped)
+	f := newFixture(t)
+
+	var (
+		tcrLocal  = newTestStatus("3", "status3")
+		tcrRemote = newTestCR("resource1", "spec1", "status0")
+	)
+	f.addLocalObjects(tcrLocal)
+	f.addRemoteObjects(tcrRemote)
+
+	crs, gvr := f.newCRSyncer(crd, "")
+	defer crs.stop()
+	crs.journal = newStatusJournal(t.TempDir(), 10)
+	// The status changed twice while the cloud was unreachable.
+	for _, o := range []*unstructured.Unstructured{
+		newTestStatus("1", "status1"),
+		newTestStatus("2", "status2"),
+		tcrLocal,
+	} {
+		if err := crs.journal.record("default/resource1", o); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	crs.startInformers()
+	if err := crs.syncDownstream("default/resource1"); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, rv := range []string{"1", "2", "3"} {
+		tcrRemoteNew := newTestCR("resource1", "spec1", "status"+rv)
+		tcrRemoteNew.SetAnnotations(map[string]string{
+			annotationResourceVersion: rv,
+		})
+		f.expectRemoteActions(k8stest.NewUpdateAction(gvr, "default", tcrRemoteNew))
+	}
+	f.verifyWriteActions()
+
+	if got := pendingStatuses(t, crs.journal, "default/resource1"); len(got) != 0 {
+		t.Errorf("expected journal to be empty after sync, got %v", got)
+	}
+}
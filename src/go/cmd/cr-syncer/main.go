@@ -25,6 +25,15 @@
 // If true, only sync CRs that have a label 'cloudrobotics.com/robot-name: <robot-name>'
 // that matches the robot-name arg given on the command line.
 //
+// Annotation "filter-by-cluster-name"
+//
+//   cr-syncer.cloudrobotics.com/filter-by-cluster-name: <bool>
+//
+// If true in addition to filter-by-robot-name, also sync CRs that have a label
+// 'cloudrobotics.com/cluster-name: <robot-name>'. This is used for resources
+// that target the robot as a cluster rather than as a robot, eg the
+// ChartAssignments of cloud charts that are rolled out to edge clusters.
+//
 // Annotation "status-subtree"
 //
 //   cr-syncer.cloudrobotics.com/status-subtree: <string>
@@ -39,6 +48,106 @@
 // If set to "cloud", the source of truth for object existence and specs (upstream) is
 // the remote cluster and for status it's local (downstream). If set to "robot", the roles
 // are reversed. Otherwise, eg when using the empty string "", synchronization is disabled.
+//
+// Annotation "namespace-mapping"
+//
+//   cr-syncer.cloudrobotics.com/namespace-mapping: <cloud>=<robot>,<namespace>,...
+//
+// If specified, namespaced resources are synced in the given namespaces
+// instead of the one given by --namespace. A pair maps a namespace in the
+// cloud to a different namespace on the robot, eg "tenant-foo=default".
+//
+// Annotations "cloud-owned-fields" and "robot-owned-fields"
+//
+//   cr-syncer.cloudrobotics.com/cloud-owned-fields: <path>,<path>,...
+//   cr-syncer.cloudrobotics.com/robot-owned-fields: <path>,<path>,...
+//
+// If specified, the fields at the given paths, eg "spec.target", are synced
+// from their owner regardless of the spec source, so that resources can be
+// edited on both sides. Fields that are owned by neither side are synced as
+// usual. If a field is changed by the side that doesn't own it, the owner
+// wins: the change is reverted and a warning Event is recorded on the
+// resource in the robot's cluster. Owned fields must not be in the metadata
+// or status.
+//
+// Robot-owned fields require the spec source "robot". The cr-syncer would have
+// to write them to the spec of resources in the cloud otherwise, but the RBAC
+// policy of the cloud only lets robots update the status of cloud-sourced
+// resources, or a robot could run code on other robots. Use cloud-owned fields
+// of robot-sourced resources to let the cloud edit parts of a resource instead.
+//
+// Annotation "status-journal"
+//
+//   cr-syncer.cloudrobotics.com/status-journal: <int>
+//
+// If set to a positive number and the spec source is "cloud", every status
+// change on the robot is journaled on disk in the directory given by
+// --journal-dir, up to the given number of changes per resource. If the
+// cloud is unreachable, the changes are replayed in order once it's
+// reachable again, instead of only syncing the latest status. The journal of
+// a resource is kept until its resource on the robot is deleted. The robot
+// chart mounts a host directory as --journal-dir if cr_syncer.journal is
+// "true".
+//
+// Annotations "deletion-policy" and "deletion-grace-period"
+//
+//   cr-syncer.cloudrobotics.com/deletion-policy: immediate|delay|orphan
+//   cr-syncer.cloudrobotics.com/deletion-grace-period: <duration>
+//
+// Controls what happens to the downstream resource when the upstream
+// resource is deleted. By default ("immediate"), it is deleted as well. With
+// "delay", it is marked with a cr-syncer.cloudrobotics.com/delete-after
+// tombstone and only deleted after the grace period, eg "10m", unless the
+// upstream resource is recreated before. With "orphan", it is kept and
+// marked with a cr-syncer.cloudrobotics.com/orphaned annotation. The marks
+// are removed when the upstream resource is recreated.
+//
+// SyncRules
+//
+// Resources can also be synced by creating a SyncRule in the local cluster,
+// which doesn't require owning the CRD and works for built-in types:
+//
+//   apiVersion: cr-syncer.cloudrobotics.com/v1alpha1
+//   kind: SyncRule
+//   metadata:
+//     name: robot-configmaps
+//   spec:
+//     version: v1
+//     resource: configmaps
+//     specSource: cloud
+//     labelSelector: cloudrobotics.com/sync=true
+//     specFields: [data, binaryData]
+//     ignoreStatus: true
+//     namespaces:
+//     - cloud: tenant-foo
+//       robot: default
+//     ownedFields:
+//       cloud: [data.target]
+//     deletion:
+//       policy: delay
+//       gracePeriod: 10m
+//
+// A SyncRule takes precedence over the annotations of the resource's CRD.
+//
+// Authentication
+//
+// By default, the cr-syncer authenticates to the cloud cluster with Google
+// default credentials. Robots without a metadata server can use a bearer
+// token file, eg a projected service account token, a TLS client
+// certificate or an exec credential plugin instead, see --remote-auth.
+// Rotated tokens and certificates are picked up without a restart.
+//
+// Health checks
+//
+// /healthz is meant for the liveness probe and only checks the cr-syncer
+// itself: it fails if the main loop is stuck or the remote cluster rejects
+// the credentials as Unauthorized. It doesn't fail while the remote cluster is
+// unreachable, which a restart wouldn't fix.
+//
+// /readyz fails if a syncer hasn't heard from the remote cluster for
+// --max-remote-silence or failed --max-consecutive-errors syncs in a row. It
+// also fails until the informers of all syncers are synced and while a syncer
+// has more than --max-queue-depth queued resources.
 package main
 
 import (
@@ -55,8 +164,6 @@ import (
 	"go.opencensus.io/tag"
 	"go.opencensus.io/zpages"
 	"golang.org/x/net/context"
-	"golang.org/x/oauth2"
-	"golang.org/x/oauth2/google"
 	crdtypes "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	crdclientset "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
 	crdinformer "k8s.io/apiextensions-apiserver/pkg/client/informers/externalversions"
@@ -83,6 +190,22 @@ var (
 	conflictErrorLimit = flag.Int("conflict-error-limit", 5, "Number of consecutive conflict errors before informer is restarted")
 	timeout            = flag.Int64("timeout", 300, "Timeout for CR watch calls in seconds")
 	namespace          = flag.String("namespace", metav1.NamespaceDefault, "Namespace which namespaced resources are synced")
+	remoteCompression  = flag.Bool("remote-compression", true, "Request gzip-compressed responses from the remote cluster")
+	pruneFields        = flag.Bool("prune-fields", true, "Drop managedFields from cached resources and don't sync the last-applied-configuration annotation. managedFields are still downloaded, so this reduces memory, not transfer")
+	journalDir         = flag.String("journal-dir", "", "Directory for the status journal of resources that enable it. If empty, the journal is disabled")
+	journalOwner       = flag.String("chown-journal-dir", "", "If set to <uid>:<gid>, create --journal-dir, hand it over to that user and exit. The robot chart runs this as root in an init container, since host directories are created as root")
+
+	maxRemoteSilence     = flag.Duration("max-remote-silence", 15*time.Minute, "Time without successful list or watch requests to the remote cluster after which /readyz fails. Zero disables the check")
+	maxConsecutiveErrors = flag.Int("max-consecutive-errors", 50, "Consecutive sync errors of a syncer's upstream or downstream queue, without a successful sync of any resource in between, after which /readyz fails. Zero disables the check")
+	maxQueueDepth        = flag.Int("max-queue-depth", 1000, "Queued resources of a syncer above which /readyz fails. Zero disables the check")
+
+	remoteAuth        = flag.String("remote-auth", authGoogle, "Credentials for the remote cluster: \"google\", \"token-file\", \"client-cert\" or \"exec\"")
+	remoteCAFile      = flag.String("remote-ca-file", "", "CA certificate file of the remote cluster. If empty, the system's CAs are used")
+	remoteTokenFile   = flag.String("remote-token-file", "/var/run/secrets/tokens/cr-syncer", "Bearer token file for --remote-auth=token-file, eg a projected service account token. It is reread periodically to pick up rotated tokens")
+	remoteCertFile    = flag.String("remote-cert-file", "", "Client certificate file for --remote-auth=client-cert. It is reloaded when it changes")
+	remoteKeyFile     = flag.String("remote-key-file", "", "Client key file for --remote-auth=client-cert")
+	remoteExecCommand = flag.String("remote-exec-command", "", "Credential plugin for --remote-auth=exec, with arguments separated by spaces")
+	remoteExecVersion = flag.String("remote-exec-api-version", "client.authentication.k8s.io/v1beta1", "API version of the ExecCredential of the credential plugin")
 
 	sizeDistribution    = view.Distribution(0, 1024, 2048, 4096, 16384, 65536, 262144, 1048576, 4194304, 33554432)
 	latencyDistribution = view.Distribution(0, 1, 2, 5, 10, 15, 25, 50, 100, 200, 400, 800, 1500, 3000, 6000)
@@ -138,7 +261,13 @@ func (r *ctxRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
 
 // restConfigForRemote assembles the K8s REST config for the remote server.
 func restConfigForRemote(ctx context.Context) (*rest.Config, error) {
-	tokenSource, err := google.DefaultTokenSource(ctx, "https://www.googleapis.com/auth/cloud-platform")
+	config := &rest.Config{
+		Host: fmt.Sprintf("https://%s", *remoteServer),
+		// The original value of timeout is set in the options of lister and watcher in newInformer function. This timeout is not enforced by the client.
+		// That's the reason for the timeout in REST config. It is set to timeout + 5 seconds to give some time for a graceful closing of the connection.
+		Timeout: time.Second * (time.Duration(*timeout) + 5),
+	}
+	authTransport, err := configureRemoteAuth(ctx, config)
 	if err != nil {
 		return nil, err
 	}
@@ -146,24 +275,15 @@ func restConfigForRemote(ctx context.Context) (*rest.Config, error) {
 	if err != nil {
 		return nil, err
 	}
-	transport := func(base http.RoundTripper) (rt http.RoundTripper) {
-		rt = &oauth2.Transport{
-			Source: tokenSource,
-			Base:   base,
-		}
+	config.WrapTransport = func(base http.RoundTripper) (rt http.RoundTripper) {
+		rt = authTransport(&meteredTransport{base: base, compress: *remoteCompression})
 		if *verbose {
 			rt = &loghttp.Transport{Transport: rt}
 		}
 		rt = &ochttp.Transport{Base: rt}
 		return &ctxRoundTripper{base: rt, ctx: ctx}
 	}
-	return &rest.Config{
-		Host:          fmt.Sprintf("https://%s", *remoteServer),
-		WrapTransport: transport,
-		// The original value of timeout is set in the options of lister and watcher in newInformer function. This timeout is not enforced by the client.
-		// That's the reason for the timeout in REST config. It is set to timeout + 5 seconds to give some time for a graceful closing of the connection.
-		Timeout: time.Second * (time.Duration(*timeout) + 5),
-	}, nil
+	return config, nil
 }
 
 type CrdChange struct {
@@ -202,6 +322,13 @@ func main() {
 	flag.Parse()
 	ctx := context.Background()
 
+	if *journalOwner != "" {
+		if err := chownJournalDir(*journalDir, *journalOwner); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
 	localConfig, err := rest.InClusterConfig()
 	if err != nil {
 		log.Fatal(err)
@@ -239,6 +366,13 @@ func main() {
 	zpages.Handle(nil, "/debug")
 	http.Handle("/metrics", exporter)
 	http.Handle("/health", newHealthHandler(ctx, remote))
+	syncers := newSyncerSet(ctx, local, remote, *robotName)
+	if syncers.recorder, err = newEventRecorder(localConfig); err != nil {
+		log.Fatal(err)
+	}
+	loop := newHeartbeat()
+	http.Handle("/healthz", newLivenessHandler(ctx, remote, loop))
+	http.Handle("/readyz", newReadinessHandler(syncers))
 
 	go func() {
 		if err := http.ListenAndServe(*listenAddr, nil); err != nil {
@@ -250,30 +384,19 @@ func main() {
 	if err := streamCrds(ctx.Done(), crdclientset.NewForConfigOrDie(localConfig), crds); err != nil {
 		log.Fatalf("Unable to stream CRDs from local Kubernetes: %v", err)
 	}
-	syncers := make(map[string]*crSyncer)
-	for crd := range crds {
-		name := crd.CRD.GetName()
+	rules := make(chan SyncRuleChange)
+	streamSyncRules(ctx.Done(), local, rules)
 
-		if cur, ok := syncers[name]; ok {
-			if crd.Type == watch.Added {
-				log.Printf("Warning: Already had a running sync for freshly added %s", name)
-			}
-			cur.stop()
-			delete(syncers, name)
-		}
-		if crd.Type == watch.Added || crd.Type == watch.Modified {
-			// The modify procedure is very heavyweight: We throw away
-			// the informer for the CRD (read: all cached data) on every
-			// modification and recreate it. If that ever turns out to
-			// be a problem, we should use a shared informer cache
-			// instead.
-			s, err := newCRSyncer(ctx, *crd.CRD, local, remote, *robotName)
-			if err != nil {
-				log.Printf("skipping custom resource %s: %s", name, err)
-				continue
-			}
-			syncers[name] = s
-			go s.run()
+	ticker := time.NewTicker(loopStallTimeout / 4)
+	defer ticker.Stop()
+	for {
+		select {
+		case crd := <-crds:
+			syncers.handleCRD(crd)
+		case rule := <-rules:
+			syncers.handleSyncRule(rule)
+		case <-ticker.C:
+			loop.beat()
 		}
 	}
 }
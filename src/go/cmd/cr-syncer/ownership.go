@@ -0,0 +1,235 @@
+// Copyright 2026 The Cloud Robotics Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"log"
+	"reflect"
+	"strings"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/record"
+)
+
+const (
+	// Annotations attached to CRDs that list the paths of fields owned by
+	// the cloud or the robot, eg "spec.target,spec.deadline".
+	annotationCloudOwnedFields = "cr-syncer.cloudrobotics.com/cloud-owned-fields"
+	annotationRobotOwnedFields = "cr-syncer.cloudrobotics.com/robot-owned-fields"
+
+	reasonOwnedFieldConflict = "OwnedFieldConflict"
+)
+
+// parseFieldPaths parses a comma-separated list of field paths.
+func parseFieldPaths(s string) []string {
+	var res []string
+	for _, p := range strings.Split(s, ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			res = append(res, p)
+		}
+	}
+	return res
+}
+
+// validateOwnedFields checks that owned fields are outside of the metadata
+// and status, which are synced separately, and that no field is owned by
+// both sides.
+//
+// Robot-owned fields are only supported if the robot is the spec source.
+// Otherwise the cr-syncer would have to update the spec in the cloud, but
+// robots may only update the status of cloud resources, or they could run
+// code on other robots.
+func validateOwnedFields(specSource string, cloud, robot []string) error {
+	if len(robot) > 0 && specSource != "robot" {
+		return fmt.Errorf("robot-owned fields require spec source \"robot\"")
+	}
+	for _, p := range append(append([]string{}, cloud...), robot...) {
+		path := strings.Split(p, ".")
+		for _, f := range path {
+			if f == "" {
+				return fmt.Errorf("invalid field path %q", p)
+			}
+		}
+		switch path[0] {
+		case "apiVersion", "kind", "metadata", "status":
+			return fmt.Errorf("field %q can't be owned", p)
+		}
+	}
+	for _, c := range cloud {
+		for _, r := range robot {
+			if isFieldPrefix(c, r) || isFieldPrefix(r, c) {
+				return fmt.Errorf("field %q overlaps with robot-owned field %q", c, r)
+			}
+		}
+	}
+	return nil
+}
+
+// isFieldPrefix returns true if the field at path a contains the field at
+// path b.
+func isFieldPrefix(a, b string) bool {
+	return a == b || strings.HasPrefix(b, a+".")
+}
+
+func splitFieldPaths(paths []string) [][]string {
+	var res [][]string
+	for _, p := range paths {
+		res = append(res, strings.Split(p, "."))
+	}
+	return res
+}
+
+// ownedValue is the value of an owned field. ok is false if the field is
+// unset.
+type ownedValue struct {
+	value interface{}
+	ok    bool
+}
+
+func getOwnedValue(o *unstructured.Unstructured, path []string) ownedValue {
+	v, ok, err := unstructured.NestedFieldNoCopy(o.Object, path...)
+	if err != nil {
+		// A parent of the field isn't a map, so the field is unset.
+		return ownedValue{}
+	}
+	return ownedValue{value: v, ok: ok}
+}
+
+func setOwnedValue(o *unstructured.Unstructured, path []string, v ownedValue) error {
+	if !v.ok {
+		unstructured.RemoveNestedField(o.Object, path...)
+		return nil
+	}
+	return unstructured.SetNestedField(o.Object, v.value, path...)
+}
+
+func (v ownedValue) equal(w ownedValue) bool {
+	return v.ok == w.ok && reflect.DeepEqual(v.value, w.value)
+}
+
+// ownedFields are the fields of a resource that are owned by one side and
+// copied from its owner regardless of the spec source. To detect conflicts,
+// the values that were last written to the other side are kept: if the
+// other side changed a field since, the change is reverted and an Event is
+// recorded. As the values aren't persisted, changes made while the
+// cr-syncer restarts are reverted without an Event.
+type ownedFields struct {
+	paths [][]string
+
+	mu sync.Mutex
+	// synced holds the values by queue key and field path.
+	synced map[string]map[string]ownedValue
+}
+
+func newOwnedFields(paths []string) *ownedFields {
+	return &ownedFields{
+		paths:  splitFieldPaths(paths),
+		synced: map[string]map[string]ownedValue{},
+	}
+}
+
+// differ returns true if any owned field differs between a and b.
+func (f *ownedFields) differ(a, b *unstructured.Unstructured) bool {
+	for _, p := range f.paths {
+		if !getOwnedValue(a, p).equal(getOwnedValue(b, p)) {
+			return true
+		}
+	}
+	return false
+}
+
+// get returns a copy of the values of the owned fields of o.
+func (f *ownedFields) get(o *unstructured.Unstructured) map[string]ownedValue {
+	values := map[string]ownedValue{}
+	for _, p := range f.paths {
+		v := getOwnedValue(o, p)
+		v.value = runtime.DeepCopyJSONValue(v.value)
+		values[strings.Join(p, ".")] = v
+	}
+	return values
+}
+
+// set sets the owned fields of o to the given values.
+func (f *ownedFields) set(o *unstructured.Unstructured, values map[string]ownedValue) error {
+	for _, p := range f.paths {
+		if err := setOwnedValue(o, p, values[strings.Join(p, ".")]); err != nil {
+			return fmt.Errorf("failed to set %s: %s", strings.Join(p, "."), err)
+		}
+	}
+	return nil
+}
+
+// conflicts returns the paths of owned fields that were changed in dst since
+// they were last synced and differ from the owner's resource src.
+func (f *ownedFields) conflicts(key string, src, dst *unstructured.Unstructured) []string {
+	f.mu.Lock()
+	synced := f.synced[key]
+	f.mu.Unlock()
+
+	var conflicts []string
+	for _, p := range f.paths {
+		path := strings.Join(p, ".")
+		cur := getOwnedValue(dst, p)
+		if last, ok := synced[path]; ok && !cur.equal(last) && !cur.equal(getOwnedValue(src, p)) {
+			conflicts = append(conflicts, path)
+		}
+	}
+	return conflicts
+}
+
+// written records the values that were written for key.
+func (f *ownedFields) written(key string, values map[string]ownedValue) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.synced[key] = values
+}
+
+// forget drops the values recorded for key, eg when the resource is
+// deleted.
+func (f *ownedFields) forget(key string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.synced, key)
+}
+
+// reportConflicts records an Event on obj for every field owned by owner
+// ("cloud" or "robot") whose change by the other side was reverted.
+func (s *crSyncer) reportConflicts(obj *unstructured.Unstructured, owner string, conflicts []string) {
+	for _, path := range conflicts {
+		msg := fmt.Sprintf("Reverted change of field %s as it is owned by the %s", path, owner)
+		log.Printf("%s %s/%s: %s", obj.GetKind(), obj.GetNamespace(), obj.GetName(), msg)
+		if s.recorder != nil {
+			s.recorder.Event(obj, corev1.EventTypeWarning, reasonOwnedFieldConflict, msg)
+		}
+	}
+}
+
+// newEventRecorder returns a recorder for Events in the local cluster.
+func newEventRecorder(config *rest.Config) (record.EventRecorder, error) {
+	client, err := typedcorev1.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	broadcaster := record.NewBroadcaster()
+	broadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: client.Events("")})
+	return broadcaster.NewRecorder(scheme.Scheme, corev1.EventSource{Component: "cr-syncer"}), nil
+}
@@ -0,0 +1,223 @@
+// Copyright 2026 The Cloud Robotics Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"strings"
+	"testing"
+
+	crdtypes "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	k8stest "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/record"
+)
+
+func TestValidateOwnedFields(t *testing.T) {
+	tests := []struct {
+		desc         string
+		specSource   string
+		cloud, robot []string
+		wantErr      bool
+	}{
+		{desc: "disjoint", specSource: "robot", cloud: []string{"spec.target"}, robot: []string{"spec.progress", "data"}},
+		{desc: "similar names", specSource: "robot", cloud: []string{"spec.target"}, robot: []string{"spec.targetReached"}},
+		{desc: "cloud-owned", specSource: "cloud", cloud: []string{"spec.target"}},
+		{desc: "robot-owned of cloud spec", specSource: "cloud", robot: []string{"spec.progress"}, wantErr: true},
+		{desc: "same field", specSource: "robot", cloud: []string{"spec.target"}, robot: []string{"spec.target"}, wantErr: true},
+		{desc: "nested field", specSource: "robot", cloud: []string{"spec"}, robot: []string{"spec.progress"}, wantErr: true},
+		{desc: "status", specSource: "robot", robot: []string{"status.progress"}, wantErr: true},
+		{desc: "metadata", specSource: "robot", cloud: []string{"metadata.labels"}, wantErr: true},
+		{desc: "empty segment", specSource: "robot", cloud: []string{"spec..target"}, wantErr: true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			err := validateOwnedFields(tc.specSource, tc.cloud, tc.robot)
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Errorf("validateOwnedFields(%q, %v, %v) = %v, want error: %t", tc.specSource, tc.cloud, tc.robot, err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestSyncConfigFromCRD_invalidOwnedFields(t *testing.T) {
+	crd := testCRD(crdtypes.NamespaceScoped)
+	crd.Annotations[annotationSpecSource] = "robot"
+	crd.Annotations[annotationCloudOwnedFields] = "spec"
+	crd.Annotations[annotationRobotOwnedFields] = "spec.progress"
+	if _, err := syncConfigFromCRD(crd); err == nil {
+		t.Error("expected error for overlapping owned fields, got none")
+	}
+
+	// Robots must not write to the spec of cloud-sourced resources.
+	crd = testCRD(crdtypes.NamespaceScoped)
+	crd.Annotations[annotationRobotOwnedFields] = "spec.progress"
+	if _, err := syncConfigFromCRD(crd); err == nil {
+		t.Error("expected error for robot-owned fields of cloud spec source, got none")
+	}
+}
+
+func TestSyncUpstream_keepsDownstreamOwnedFields(t *testing.T) {
+	crd := testCRD(crdtypes.NamespaceScoped)
+	crd.Annotations[annotationSpecSource] = "robot"
+	crd.Annotations[annotationCloudOwnedFields] = "spec.progress"
+	f := newFixture(t)
+
+	var (
+		tcrLocal = newTestCR("resource1", map[string]interface{}{
+			"target":   "b",
+			"progress": "0",
+		}, "status1")
+		tcrRemote = newTestCR("resource1", map[string]interface{}{
+			"target":   "a",
+			"progress": "50",
+		}, "status1")
+	)
+	f.addLocalObjects(tcrLocal)
+	f.addRemoteObjects(tcrRemote)
+
+	crs, gvr := f.newCRSyncer(crd, "")
+	defer crs.stop()
+
+	crs.startInformers()
+	if err := crs.syncUpstream("default/resource1"); err != nil {
+		t.Fatal(err)
+	}
+	if got := crs.downstreamQueue.Len(); got != 1 {
+		t.Errorf("expected downstream sync to revert the progress on the robot, got %d queued keys", got)
+	}
+
+	f.expectRemoteActions(k8stest.NewUpdateAction(gvr, "default", newTestCR("resource1", map[string]interface{}{
+		"target":   "b",
+		"progress": "50",
+	}, "status1")))
+	f.verifyWriteActions()
+}
+
+func TestSyncDownstream_copiesDownstreamOwnedFields(t *testing.T) {
+	crd := testCRD(crdtypes.NamespaceScoped)
+	crd.Annotations[annotationSpecSource] = "robot"
+	crd.Annotations[annotationCloudOwnedFields] = "spec.progress"
+	crd.Spec.Versions[0].Subresources = &crdtypes.CustomResourceSubresources{
+		Status: &crdtypes.CustomResourceSubresourceStatus{},
+	}
+	f := newFixture(t)
+
+	var (
+		tcrRemote = newTestCR("resource1", map[string]interface{}{
+			"target":   "a",
+			"progress": "50",
+		}, "status2")
+		tcrLocal = newTestCR("resource1", map[string]interface{}{
+			"target": "a",
+		}, "status1")
+	)
+	tcrRemote.SetResourceVersion("123")
+	f.addLocalObjects(tcrLocal)
+	f.addRemoteObjects(tcrRemote)
+
+	crs, gvr := f.newCRSyncer(crd, "")
+	defer crs.stop()
+
+	crs.startInformers()
+	if err := crs.syncDownstream("default/resource1"); err != nil {
+		t.Fatal(err)
+	}
+
+	// The owned field is outside of the status subresource and needs a
+	// separate update.
+	tcrLocalNew := newTestCR("resource1", map[string]interface{}{
+		"target":   "a",
+		"progress": "50",
+	}, "status2")
+	tcrLocalNew.SetAnnotations(map[string]string{
+		annotationResourceVersion: "123",
+	})
+	f.expectLocalActions(
+		k8stest.NewUpdateAction(gvr, "default", tcrLocalNew),
+		k8stest.NewUpdateSubresourceAction(gvr, "status", "default", tcrLocalNew),
+	)
+	f.verifyWriteActions()
+}
+
+func TestSyncUpstream_revertsConflictingChange(t *testing.T) {
+	crd := testCRD(crdtypes.NamespaceScoped)
+	crd.Annotations[annotationCloudOwnedFields] = "spec.target"
+	f := newFixture(t)
+
+	var (
+		tcrRemote = newTestCR("resource1", map[string]interface{}{
+			"target":   "a",
+			"progress": "50",
+		}, "status1")
+		// The target was changed on the robot after it was synced.
+		tcrLocal = newTestCR("resource1", map[string]interface{}{
+			"target":   "x",
+			"progress": "50",
+		}, "status1")
+	)
+	f.addLocalObjects(tcrLocal)
+	f.addRemoteObjects(tcrRemote)
+
+	crs, gvr := f.newCRSyncer(crd, "")
+	defer crs.stop()
+	recorder := record.NewFakeRecorder(10)
+	crs.recorder = recorder
+	crs.upstreamOwned.written("default/resource1", crs.upstreamOwned.get(tcrRemote))
+
+	crs.startInformers()
+	if err := crs.syncUpstream("default/resource1"); err != nil {
+		t.Fatal(err)
+	}
+
+	f.expectLocalActions(k8stest.NewUpdateAction(gvr, "default", tcrRemote))
+	f.verifyWriteActions()
+
+	select {
+	case e := <-recorder.Events:
+		if !strings.Contains(e, reasonOwnedFieldConflict) || !strings.Contains(e, "spec.target") {
+			t.Errorf("unexpected event %q", e)
+		}
+	default:
+		t.Error("expected conflict event")
+	}
+}
+
+func TestSyncUpstream_noConflictWithoutSyncedValue(t *testing.T) {
+	crd := testCRD(crdtypes.NamespaceScoped)
+	crd.Annotations[annotationCloudOwnedFields] = "spec.target"
+	f := newFixture(t)
+
+	var (
+		tcrRemote = newTestCR("resource1", map[string]interface{}{"target": "b"}, "status1")
+		tcrLocal  = newTestCR("resource1", map[string]interface{}{"target": "a"}, "status1")
+	)
+	f.addLocalObjects(tcrLocal)
+	f.addRemoteObjects(tcrRemote)
+
+	crs, gvr := f.newCRSyncer(crd, "")
+	defer crs.stop()
+	recorder := record.NewFakeRecorder(10)
+	crs.recorder = recorder
+
+	crs.startInformers()
+	if err := crs.syncUpstream("default/resource1"); err != nil {
+		t.Fatal(err)
+	}
+
+	f.expectLocalActions(k8stest.NewUpdateAction(gvr, "default", tcrRemote))
+	f.verifyWriteActions()
+	if len(recorder.Events) != 0 {
+		t.Errorf("expected no events, got %q", <-recorder.Events)
+	}
+}
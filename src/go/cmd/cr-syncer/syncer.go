@@ -15,11 +15,16 @@
 package main
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"log"
 	"net/http"
+	"path/filepath"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/pkg/errors"
@@ -32,9 +37,11 @@ import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/workqueue"
 )
 
@@ -43,9 +50,16 @@ const (
 	annotationStatusSubtree     = "cr-syncer.cloudrobotics.com/status-subtree"
 	annotationFilterByRobotName = "cr-syncer.cloudrobotics.com/filter-by-robot-name"
 	annotationSpecSource        = "cr-syncer.cloudrobotics.com/spec-source"
+	annotationNamespaceMapping  = "cr-syncer.cloudrobotics.com/namespace-mapping"
+
+	// filter-by-cluster-name additionally syncs CRs that are labelled with
+	// the robot name as cluster-name, eg ChartAssignments of cloud charts
+	// for edge clusters.
+	annotationFilterByClusterName = "cr-syncer.cloudrobotics.com/filter-by-cluster-name"
 
 	// Annotations and labels attached to CRs.
-	labelRobotName = "cloudrobotics.com/robot-name"
+	labelRobotName   = "cloudrobotics.com/robot-name"
+	labelClusterName = "cloudrobotics.com/cluster-name"
 	// Annotation for remote resource version. Note that for resources in
 	// the cloud cluster, this is a resource version on the robot's cluster
 	// (and vice versa). This will only be set when the status subresource
@@ -67,8 +81,19 @@ var (
 		"Synchronization errors on resource events",
 		stats.UnitDimensionless,
 	)
+	mInformerRestarts = stats.Int64(
+		"cr-syncer.cloudrobotics.com/informer_restarts",
+		"Informers that were recreated and relisted their resources",
+		stats.UnitDimensionless,
+	)
+	mSyncerRestarts = stats.Int64(
+		"cr-syncer.cloudrobotics.com/syncer_restarts",
+		"Syncers that were restarted because their config changed",
+		stats.UnitDimensionless,
+	)
 	tagEventSource = mustNewTagKey("event_source")
 	tagResource    = mustNewTagKey("resource")
+	tagCluster     = mustNewTagKey("cluster")
 )
 
 func init() {
@@ -87,6 +112,20 @@ func init() {
 			TagKeys:     []tag.Key{tagEventSource, tagResource},
 			Aggregation: view.Count(),
 		},
+		&view.View{
+			Name:        "cr-syncer.cloudrobotics.com/informer_restarts_total",
+			Description: "Total number of informers that were recreated and relisted their resources",
+			Measure:     mInformerRestarts,
+			TagKeys:     []tag.Key{tagResource, tagCluster},
+			Aggregation: view.Count(),
+		},
+		&view.View{
+			Name:        "cr-syncer.cloudrobotics.com/syncer_restarts_total",
+			Description: "Total number of syncers that were restarted because their config changed",
+			Measure:     mSyncerRestarts,
+			TagKeys:     []tag.Key{tagResource},
+			Aggregation: view.Count(),
+		},
 	); err != nil {
 		panic(err)
 	}
@@ -125,12 +164,19 @@ func removeFinalizer(ctx context.Context, client dynamic.ResourceInterface, obj
 type crSyncer struct {
 	ctx           context.Context
 	clusterName   string // Name of downstream cluster.
-	crd           crdtypes.CustomResourceDefinition
+	cfg           syncConfig
 	upstream      dynamic.ResourceInterface // Source of the spec.
 	downstream    dynamic.ResourceInterface // Source of the status.
 	labelSelector string
+	fieldSelector string
 	subtree       string
-	versionIx     int
+	// robotLabel is the label that selects the resources of this robot,
+	// if they are filtered by robot.
+	robotLabel string
+	// Namespaces of namespaced resources in the upstream and downstream
+	// cluster. Queue keys always use the upstream namespace.
+	upstreamNs   string
+	downstreamNs string
 
 	// Informers and the queues they feed. Upstream/downstream describes
 	// the source of the change events, _not_ the direction they are heading.
@@ -142,11 +188,96 @@ type crSyncer struct {
 	downstreamQueue workqueue.RateLimitingInterface
 	infDone         chan struct{}
 
+	// informers shares informers with other syncers. If nil, the syncer
+	// runs its own informers.
+	informers        *informerCache
+	upstreamInfKey   informerKey
+	downstreamInfKey informerKey
+	upstreamShared   *sharedInformer
+	downstreamShared *sharedInformer
+	// unsubscribe removes the handlers from the shared informers.
+	unsubscribe []func()
+
+	// Fields owned by the upstream and downstream cluster.
+	upstreamOwned   *ownedFields
+	downstreamOwned *ownedFields
+	// recorder records Events for resources in the local cluster. It may
+	// be nil.
+	recorder record.EventRecorder
+	// journal records status changes of downstream resources. It is nil
+	// unless the status journal is enabled.
+	journal *statusJournal
+
 	conflictErrors int
+	health         syncerHealth
 
 	done chan struct{} // Terminates all background processes.
 }
 
+// syncerHealth is the state of a syncer that is reported by the health
+// endpoints.
+type syncerHealth struct {
+	mu      sync.Mutex
+	started time.Time
+	synced  bool
+	// errors counts the consecutive sync errors by queue.
+	errors map[string]int
+}
+
+func (h *syncerHealth) setSynced(synced bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.synced = synced
+}
+
+func (h *syncerHealth) recordSync(queue string, err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.errors == nil {
+		h.errors = map[string]int{}
+	}
+	if err == nil {
+		h.errors[queue] = 0
+	} else {
+		h.errors[queue]++
+	}
+}
+
+// status returns the state of the syncer for the health endpoints.
+func (s *crSyncer) status() syncerStatus {
+	s.health.mu.Lock()
+	defer s.health.mu.Unlock()
+
+	st := syncerStatus{
+		name:       s.cfg.name,
+		synced:     s.health.synced,
+		queueDepth: s.upstreamQueue.Len() + s.downstreamQueue.Len(),
+	}
+	if s.cfg.namespaced {
+		st.name = fmt.Sprintf("%s/%s", s.cfg.name, s.upstreamNs)
+	}
+	if s.robotLabel == labelClusterName {
+		st.name += " (by cluster-name)"
+	}
+	for _, n := range s.health.errors {
+		if n > st.consecutiveErrors {
+			st.consecutiveErrors = n
+		}
+	}
+	remoteKey := s.upstreamInfKey
+	if remoteKey.cluster != "remote" {
+		remoteKey = s.downstreamInfKey
+	}
+	last := remoteContacts.get(remoteKey)
+	if last.Before(s.health.started) {
+		last = s.health.started
+	}
+	if !last.IsZero() {
+		st.remoteSilence = time.Since(last)
+	}
+	return st
+}
+
 func getStorageVersionIndex(crd crdtypes.CustomResourceDefinition) (int, error) {
 	for ix, v := range crd.Spec.Versions {
 		if v.Storage {
@@ -156,12 +287,119 @@ func getStorageVersionIndex(crd crdtypes.CustomResourceDefinition) (int, error)
 	return 0, fmt.Errorf("invalid Custom Resource %s: no version with stored=true set", crd.ObjectMeta.Name)
 }
 
-func newCRSyncer(
-	ctx context.Context,
-	crd crdtypes.CustomResourceDefinition,
-	local, remote dynamic.Interface,
-	robotName string,
-) (*crSyncer, error) {
+// syncConfig describes how a resource is synced. It is derived from the
+// annotations of a CRD or from a SyncRule.
+type syncConfig struct {
+	// name identifies the synced resource as <resource>.<group>, which is
+	// the name of the CRD for custom resources.
+	name string
+	// origin is the CRD or SyncRule the config was derived from.
+	origin string
+
+	gvr               schema.GroupVersionResource
+	namespaced        bool
+	statusSubresource bool
+	specSource        string
+	statusSubtree     string
+	filterByRobotName bool
+	labelSelector     string
+	fieldSelector     string
+	// filterByClusterName runs a second syncer for the resources labelled
+	// with the robot name as cluster-name if filterByRobotName is set.
+	filterByClusterName bool
+	// specFields are the top-level fields that are copied from the spec
+	// source.
+	specFields []string
+	// ignoreStatus disables syncing the status back to the spec source,
+	// e.g. for resources without a status.
+	ignoreStatus bool
+	// namespaces are the synced namespaces of namespaced resources.
+	// Defaults to the namespace given by the --namespace flag on both
+	// sides.
+	namespaces []namespaceMapping
+	// cloudOwnedFields and robotOwnedFields are paths of fields, eg
+	// "spec.target", that are synced from their owner regardless of the
+	// spec source.
+	cloudOwnedFields []string
+	robotOwnedFields []string
+	// statusJournal is the maximum number of unsynced status changes that
+	// are journaled per resource. Zero disables the journal.
+	statusJournal int
+	// deletionPolicy is how the deletion of an upstream resource is
+	// propagated, see the deletion-policy annotation. deletionGracePeriod
+	// is the delay of the "delay" policy.
+	deletionPolicy      string
+	deletionGracePeriod time.Duration
+}
+
+// namespaceMapping maps a namespace in the cloud cluster to a namespace on
+// the robot.
+type namespaceMapping struct {
+	cloud string
+	robot string
+}
+
+// parseNamespaceMapping parses a comma-separated list of namespaces. An
+// entry is either a pair "<cloud>=<robot>" or a single namespace that is
+// used on both sides.
+func parseNamespaceMapping(s string) ([]namespaceMapping, error) {
+	var res []namespaceMapping
+	for _, e := range strings.Split(s, ",") {
+		e = strings.TrimSpace(e)
+		if e == "" {
+			continue
+		}
+		cloud, robot := e, e
+		if i := strings.Index(e, "="); i >= 0 {
+			cloud, robot = strings.TrimSpace(e[:i]), strings.TrimSpace(e[i+1:])
+		}
+		res = append(res, namespaceMapping{cloud: cloud, robot: robot})
+	}
+	return res, validateNamespaceMappings(res)
+}
+
+// validateNamespaceMappings checks that the namespaces are valid and that
+// every namespace is mapped at most once on either side.
+func validateNamespaceMappings(ms []namespaceMapping) error {
+	cloud, robot := map[string]bool{}, map[string]bool{}
+	for _, m := range ms {
+		if err := m.validate(); err != nil {
+			return err
+		}
+		if cloud[m.cloud] {
+			return fmt.Errorf("cloud namespace %q is mapped more than once", m.cloud)
+		}
+		if robot[m.robot] {
+			return fmt.Errorf("robot namespace %q is mapped more than once", m.robot)
+		}
+		cloud[m.cloud], robot[m.robot] = true, true
+	}
+	return nil
+}
+
+func (m namespaceMapping) validate() error {
+	for _, ns := range []string{m.cloud, m.robot} {
+		if errs := validation.IsDNS1123Label(ns); len(errs) > 0 {
+			return fmt.Errorf("invalid namespace %q: %s", ns, strings.Join(errs, ", "))
+		}
+	}
+	return nil
+}
+
+// syncedNamespaces returns the namespace mappings to run syncers for. It
+// returns a single empty mapping for cluster-scoped resources.
+func (c *syncConfig) syncedNamespaces() []namespaceMapping {
+	if !c.namespaced {
+		return []namespaceMapping{{}}
+	}
+	if len(c.namespaces) == 0 {
+		return []namespaceMapping{{cloud: *namespace, robot: *namespace}}
+	}
+	return c.namespaces
+}
+
+// syncConfigFromCRD derives the sync config from the annotations of a CRD.
+func syncConfigFromCRD(crd crdtypes.CustomResourceDefinition) (syncConfig, error) {
 	var (
 		annotations        = crd.ObjectMeta.Annotations
 		filterByRobotValue = annotations[annotationFilterByRobotName]
@@ -177,33 +415,114 @@ func newCRSyncer(
 	}
 	versionIx, err := getStorageVersionIndex(crd)
 	if err != nil {
-		return nil, errors.Wrap(err, "Bad crd passed to newCRSyncer")
+		return syncConfig{}, errors.Wrap(err, "Bad crd passed to syncConfigFromCRD")
 	}
-
+	namespaces, err := parseNamespaceMapping(annotations[annotationNamespaceMapping])
+	if err != nil {
+		return syncConfig{}, errors.Wrapf(err, "invalid %s annotation", annotationNamespaceMapping)
+	}
+	statusJournal := 0
+	if v := annotations[annotationStatusJournal]; v != "" {
+		if statusJournal, err = strconv.Atoi(v); err != nil || statusJournal < 0 {
+			return syncConfig{}, fmt.Errorf("invalid %s annotation %q", annotationStatusJournal, v)
+		}
+	}
+	cloudOwned := parseFieldPaths(annotations[annotationCloudOwnedFields])
+	robotOwned := parseFieldPaths(annotations[annotationRobotOwnedFields])
+	if err := validateOwnedFields(annotations[annotationSpecSource], cloudOwned, robotOwned); err != nil {
+		return syncConfig{}, errors.Wrap(err, "invalid owned fields")
+	}
+	filterByCluster := false
+	if v := annotations[annotationFilterByClusterName]; v != "" {
+		if filterByCluster, err = strconv.ParseBool(v); err != nil {
+			return syncConfig{}, fmt.Errorf("invalid %s annotation %q", annotationFilterByClusterName, v)
+		}
+	}
+	deletionPolicy, deletionGracePeriod, err := parseDeletionPolicy(
+		annotations[annotationDeletionPolicy], annotations[annotationDeletionGracePeriod])
+	if err != nil {
+		return syncConfig{}, errors.Wrapf(err, "invalid %s annotation", annotationDeletionPolicy)
+	}
+	v := crd.Spec.Versions[versionIx]
 	gvr := schema.GroupVersionResource{
 		Group:    crd.Spec.Group,
-		Version:  crd.Spec.Versions[versionIx].Name,
+		Version:  v.Name,
 		Resource: crd.Spec.Names.Plural,
 	}
-	ns := ""
-	if crd.Spec.Scope == crdtypes.NamespaceScoped {
-		// TODO(https://github.com/googlecloudrobotics/core/issues/19): allow syncing CRs in other namespaces
-		ns = *namespace
+	return syncConfig{
+		name:              gvr.GroupResource().String(),
+		origin:            "CRD " + crd.Name,
+		gvr:               gvr,
+		namespaced:        crd.Spec.Scope == crdtypes.NamespaceScoped,
+		statusSubresource: v.Subresources != nil && v.Subresources.Status != nil,
+		specSource:        annotations[annotationSpecSource],
+		statusSubtree:     annotations[annotationStatusSubtree],
+		filterByRobotName: filterByRobot,
+		specFields:        []string{"spec"},
+		namespaces:        namespaces,
+		cloudOwnedFields:  cloudOwned,
+		robotOwnedFields:  robotOwned,
+		statusJournal:     statusJournal,
+
+		filterByClusterName: filterByCluster,
+		deletionPolicy:      deletionPolicy,
+		deletionGracePeriod: deletionGracePeriod,
+	}, nil
+}
+
+// robotLabels returns the labels that select the resources of the robot, one
+// per syncer that is run for the resource. It returns a single empty label if
+// the resources aren't filtered by robot.
+func (c *syncConfig) robotLabels(robotName string) []string {
+	if !c.filterByRobotName || robotName == "" {
+		return []string{""}
+	}
+	if c.filterByClusterName {
+		return []string{labelRobotName, labelClusterName}
 	}
+	return []string{labelRobotName}
+}
+
+// newCRSyncer returns a syncer for the resources in the given namespaces. If
+// robotLabel is set, only resources with the label set to the robot name are
+// synced.
+func newCRSyncer(
+	ctx context.Context,
+	cfg syncConfig,
+	ns namespaceMapping,
+	robotLabel string,
+	local, remote dynamic.Interface,
+	robotName string,
+) (*crSyncer, error) {
 	s := &crSyncer{
-		ctx:        ctx,
-		crd:        crd,
-		subtree:    annotations[annotationStatusSubtree],
-		versionIx:  versionIx,
-		upstream:   remote.Resource(gvr).Namespace(ns),
-		downstream: local.Resource(gvr).Namespace(ns),
-		done:       make(chan struct{}),
-	}
-	switch src := annotations[annotationSpecSource]; src {
+		ctx:           ctx,
+		cfg:           cfg,
+		robotLabel:    robotLabel,
+		subtree:       cfg.statusSubtree,
+		fieldSelector: cfg.fieldSelector,
+		upstream:      remote.Resource(cfg.gvr).Namespace(ns.cloud),
+		downstream:    local.Resource(cfg.gvr).Namespace(ns.robot),
+		upstreamNs:    ns.cloud,
+		downstreamNs:  ns.robot,
+		done:          make(chan struct{}),
+	}
+	s.upstreamOwned = newOwnedFields(cfg.cloudOwnedFields)
+	s.downstreamOwned = newOwnedFields(cfg.robotOwnedFields)
+	if cfg.statusJournal > 0 && !cfg.ignoreStatus {
+		// Only statuses in the local cluster can be journaled.
+		if *journalDir == "" || cfg.specSource != "cloud" {
+			log.Printf("Ignoring status journal of %s, which requires --journal-dir and spec source \"cloud\"", cfg.origin)
+		} else {
+			s.journal = newStatusJournal(filepath.Join(*journalDir, cfg.name), cfg.statusJournal)
+		}
+	}
+	switch src := cfg.specSource; src {
 	case "robot":
 		s.clusterName = cloudClusterName
 		// Swap upstream and downstream if the robot is the spec source.
 		s.upstream, s.downstream = s.downstream, s.upstream
+		s.upstreamNs, s.downstreamNs = s.downstreamNs, s.upstreamNs
+		s.upstreamOwned, s.downstreamOwned = s.downstreamOwned, s.upstreamOwned
 		// Use DefaultControllerRateLimiter for queue with destination robot and ItemFastSlowRateLimiter for queue with destination cloud to improve resilience regarding network errors
 		// Upstream destination is robot cluster, downstream destination is cloud cluster
 		s.upstreamQueue = workqueue.NewNamedRateLimitingQueue(workqueue.NewItemFastSlowRateLimiter(time.Millisecond*500, time.Second*5, 5), "upstream")
@@ -217,33 +536,79 @@ func newCRSyncer(
 	default:
 		return nil, fmt.Errorf("unknown spec source %q", src)
 	}
-	if filterByRobot {
-		if robotName != "" {
-			s.labelSelector = labelRobotName + "=" + robotName
+	var selectors []string
+	if cfg.labelSelector != "" {
+		selectors = append(selectors, cfg.labelSelector)
+	}
+	if cfg.filterByRobotName {
+		if robotName != "" && robotLabel != "" {
+			selectors = append(selectors, robotLabel+"="+robotName)
 		} else {
 			// TODO(fabxc): should this return an error instead?
-			log.Printf("%s requested to filter by robot-name, but no robot-name was given to cr-syncer", crd.ObjectMeta.Name)
+			log.Printf("%s requested to filter by robot-name, but no robot-name was given to cr-syncer", cfg.origin)
 		}
 	}
+	s.labelSelector = strings.Join(selectors, ",")
 
-	s.upstreamInf = s.newInformer(s.upstream)
-	s.downstreamInf = s.newInformer(s.downstream)
-
+	s.upstreamInfKey = informerKey{
+		cluster:       "remote",
+		gvr:           cfg.gvr,
+		namespace:     ns.cloud,
+		labelSelector: s.labelSelector,
+		fieldSelector: s.fieldSelector,
+	}
+	s.downstreamInfKey = s.upstreamInfKey
+	s.downstreamInfKey.cluster = "local"
+	s.downstreamInfKey.namespace = ns.robot
+	if cfg.specSource == "robot" {
+		s.upstreamInfKey, s.downstreamInfKey = s.downstreamInfKey, s.upstreamInfKey
+	}
 	return s, nil
 }
 
-func (s *crSyncer) newInformer(client dynamic.ResourceInterface) cache.SharedIndexInformer {
+// newInformer returns an informer for the resources of the given key. For
+// the remote cluster, successful requests and watch events are recorded for
+// the health endpoints.
+func (s *crSyncer) newInformer(client dynamic.ResourceInterface, key informerKey) cache.SharedIndexInformer {
+	contact := func() {
+		if key.cluster == "remote" {
+			remoteContacts.record(key)
+		}
+	}
 	return cache.NewSharedIndexInformer(
 		&cache.ListWatch{
 			ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
 				options.LabelSelector = s.labelSelector
+				options.FieldSelector = s.fieldSelector
 				options.TimeoutSeconds = timeout
-				return client.List(s.ctx, options)
+				list, err := client.List(s.ctx, options)
+				if err != nil {
+					return nil, err
+				}
+				contact()
+				for i := range list.Items {
+					pruneObject(&list.Items[i])
+				}
+				return list, nil
 			},
 			WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
 				options.LabelSelector = s.labelSelector
+				options.FieldSelector = s.fieldSelector
 				options.TimeoutSeconds = timeout
-				return client.Watch(s.ctx, options)
+				w, err := client.Watch(s.ctx, options)
+				if err != nil {
+					return nil, err
+				}
+				contact()
+				return watch.Filter(w, func(e watch.Event) (watch.Event, bool) {
+					if e.Type != watch.Error {
+						contact()
+					}
+					if u, ok := e.Object.(*unstructured.Unstructured); ok {
+						pruneObject(u)
+					}
+					return e, true
+				}), nil
 			},
 		},
 		&unstructured.Unstructured{},
@@ -252,55 +617,108 @@ func (s *crSyncer) newInformer(client dynamic.ResourceInterface) cache.SharedInd
 	)
 }
 
+// startInformers starts the informers or acquires them from the shared
+// cache and waits until they are synced.
 func (s *crSyncer) startInformers() error {
+	return s.startInformersFresh(false)
+}
+
+// startInformersFresh starts the informers. If fresh is set, shared
+// informers are recreated instead of reused.
+func (s *crSyncer) startInformersFresh(fresh bool) error {
 	if s.infDone != nil {
-		return fmt.Errorf("informer for %s already started", s.crd.GetName())
+		return fmt.Errorf("informer for %s already started", s.cfg.name)
 	}
 	s.infDone = make(chan struct{})
 
-	go s.upstreamInf.Run(s.infDone)
-	go s.downstreamInf.Run(s.infDone)
+	if s.informers != nil {
+		s.upstreamShared = s.informers.acquire(s.upstreamInfKey, func() cache.SharedIndexInformer {
+			return s.newInformer(s.upstream, s.upstreamInfKey)
+		}, fresh)
+		s.downstreamShared = s.informers.acquire(s.downstreamInfKey, func() cache.SharedIndexInformer {
+			return s.newInformer(s.downstream, s.downstreamInfKey)
+		}, fresh)
+		s.upstreamInf = s.upstreamShared.inf
+		s.downstreamInf = s.downstreamShared.inf
+	} else {
+		s.upstreamInf = s.newInformer(s.upstream, s.upstreamInfKey)
+		s.downstreamInf = s.newInformer(s.downstream, s.downstreamInfKey)
+		go s.upstreamInf.Run(s.infDone)
+		go s.downstreamInf.Run(s.infDone)
+	}
 
 	if ok := cache.WaitForCacheSync(s.infDone, s.upstreamInf.HasSynced); !ok {
-		return fmt.Errorf("stopped while syncing upstream informer for %s", s.crd.GetName())
+		return fmt.Errorf("stopped while syncing upstream informer for %s", s.cfg.name)
 	}
 	if ok := cache.WaitForCacheSync(s.infDone, s.downstreamInf.HasSynced); !ok {
-		return fmt.Errorf("stopped while syncing downstream informer for %s", s.crd.GetName())
+		return fmt.Errorf("stopped while syncing downstream informer for %s", s.cfg.name)
 	}
-	s.setupInformerHandlers(s.upstreamInf, s.upstreamQueue, "upstream")
-	s.setupInformerHandlers(s.downstreamInf, s.downstreamQueue, "downstream")
+	s.setupInformerHandlers(s.upstreamInf, s.upstreamShared, s.upstreamQueue, "upstream")
+	s.setupInformerHandlers(s.downstreamInf, s.downstreamShared, s.downstreamQueue, "downstream")
+	s.health.setSynced(true)
 
 	return nil
 }
 
 func (s *crSyncer) stopInformers() {
+	s.health.setSynced(false)
 	if s.infDone != nil {
 		close(s.infDone)
 		s.infDone = nil
 	}
+	for _, unsubscribe := range s.unsubscribe {
+		unsubscribe()
+	}
+	s.unsubscribe = nil
+	if s.upstreamShared != nil {
+		s.informers.release(s.upstreamShared)
+		s.upstreamShared = nil
+	}
+	if s.downstreamShared != nil {
+		s.informers.release(s.downstreamShared)
+		s.downstreamShared = nil
+	}
 }
 
 func (s *crSyncer) restartInformers() error {
 	s.stopInformers()
-	s.upstreamInf = s.newInformer(s.upstream)
-	s.downstreamInf = s.newInformer(s.downstream)
-	return s.startInformers()
+	if s.informers == nil {
+		recordInformerRestart(s.upstreamInfKey)
+		recordInformerRestart(s.downstreamInfKey)
+	}
+	return s.startInformersFresh(true)
 }
 
 func (s *crSyncer) setupInformerHandlers(
 	inf cache.SharedIndexInformer,
+	shared *sharedInformer,
 	queue workqueue.RateLimitingInterface,
 	direction string,
 ) {
+	// Events that are being dispatched while the syncer stops are dropped.
+	done := s.infDone
 	receive := func(obj interface{}, action string) {
+		select {
+		case <-done:
+			return
+		default:
+		}
 		u := obj.(*unstructured.Unstructured)
 		log.Printf("Got %s event from %s for %s %s@v%s",
 			action, direction, u.GetKind(), u.GetName(), u.GetResourceVersion())
 		if key, ok := keyFunc(obj); ok {
+			if direction == "downstream" {
+				key = s.upstreamKey(key)
+				if s.journal != nil && action != "delete" {
+					if err := s.journal.record(key, u); err != nil {
+						log.Printf("Failed to journal status of %s %s: %s", u.GetKind(), u.GetName(), err)
+					}
+				}
+			}
 			queue.AddRateLimited(key)
 		}
 	}
-	inf.AddEventHandler(cache.ResourceEventHandlerFuncs{
+	handler := cache.ResourceEventHandlerFuncs{
 		AddFunc: func(obj interface{}) {
 			receive(obj, "add")
 		},
@@ -310,7 +728,12 @@ func (s *crSyncer) setupInformerHandlers(
 		DeleteFunc: func(obj interface{}) {
 			receive(obj, "delete")
 		},
-	})
+	}
+	if shared != nil {
+		s.unsubscribe = append(s.unsubscribe, shared.subscribe(handler))
+	} else {
+		inf.AddEventHandler(handler)
+	}
 }
 
 func (s *crSyncer) processNextWorkItem(
@@ -332,10 +755,10 @@ func (s *crSyncer) processNextWorkItem(
 	// This could occur at watchers of single CRDs while others keep working. Thus, it is less resource intensive just restarting informers of the affected CRDs rather than whoel cr-syncer
 	// Errors are counted in syncUpstream and syncDownstream functions
 	if s.conflictErrors >= *conflictErrorLimit {
-		log.Printf("Restarting informers of %s because of too many conflict errors", s.crd.GetName())
+		log.Printf("Restarting informers of %s because of too many conflict errors", s.cfg.name)
 		err := s.restartInformers()
 		if err != nil {
-			log.Printf("Restarting informers for %s failed", s.crd.GetName())
+			log.Printf("Restarting informers for %s failed", s.cfg.name)
 			q.AddRateLimited(key)
 			return true
 		} else {
@@ -348,6 +771,7 @@ func (s *crSyncer) processNextWorkItem(
 		panic(err)
 	}
 	err = syncf(key.(string))
+	s.health.recordSync(qName, err)
 	stats.Record(ctx, mSyncs.M(1))
 	if err == nil {
 		q.Forget(key)
@@ -365,15 +789,18 @@ func (s *crSyncer) run() {
 	defer s.upstreamQueue.ShutDown()
 	defer s.downstreamQueue.ShutDown()
 
-	log.Printf("Starting syncer for %s", s.crd.GetName())
+	log.Printf("Starting syncer for %s", s.cfg.name)
+	s.health.mu.Lock()
+	s.health.started = time.Now()
+	s.health.mu.Unlock()
 
 	// Start informers that will populate their associated workqueue.
 	if err := s.startInformers(); err != nil {
-		log.Printf("Starting informers for %s failed: %s", s.crd.GetName(), err)
+		log.Printf("Starting informers for %s failed: %s", s.cfg.name, err)
 		return
 	}
 
-	ctx, err := tag.New(context.Background(), tag.Insert(tagResource, s.crd.Name))
+	ctx, err := tag.New(context.Background(), tag.Insert(tagResource, s.cfg.name))
 	if err != nil {
 		panic(err)
 	}
@@ -388,13 +815,11 @@ func (s *crSyncer) run() {
 	}()
 	<-s.done
 	// Close informers
-	if s.infDone != nil {
-		close(s.infDone)
-	}
+	s.stopInformers()
 }
 
 func (s *crSyncer) stop() {
-	log.Printf("Stopping syncer for %s", s.crd.GetName())
+	log.Printf("Stopping syncer for %s", s.cfg.name)
 	close(s.done)
 }
 
@@ -402,10 +827,8 @@ func (s *crSyncer) stop() {
 // downstream cluster. It synchronizes the status from the downstream to the
 // upstream cluster, and deletes orphaned downstream resources.
 func (s *crSyncer) syncDownstream(key string) error {
-	v := s.crd.Spec.Versions[s.versionIx]
-	statusIsSubresource := v.Subresources != nil && v.Subresources.Status != nil
 	// Get the downstream status (src) and upstream spec (dst).
-	srcObj, srcExists, err := s.downstreamInf.GetIndexer().GetByKey(key)
+	srcObj, srcExists, err := s.downstreamInf.GetIndexer().GetByKey(s.downstreamKey(key))
 	if err != nil {
 		return fmt.Errorf("failed to retrieve resource for key %s: %s", key, err)
 	}
@@ -424,75 +847,87 @@ func (s *crSyncer) syncDownstream(key string) error {
 	if err != nil {
 		return fmt.Errorf("failed to retrieve resource for key %s: %s", key, err)
 	}
-	// If the upstream resource no longer exists, delete the downstream
-	// resource. Normally, this occurs when syncUpstream() handles the
-	// upstream deletion, but if the resource was deleted when the robot
-	// was offline, upstream doesn't know about the old resource and we'll
-	// hit this condition.
+	// If the upstream resource no longer exists, propagate the deletion
+	// to the downstream resource. Normally, this occurs when
+	// syncUpstream() handles the upstream deletion, but if the resource
+	// was deleted when the robot was offline, upstream doesn't know about
+	// the old resource and we'll hit this condition.
 	if !dstExists {
-		if src.GetDeletionTimestamp() != nil {
-			return nil // Already being deleted.
-		}
-		if err := s.downstream.Delete(s.ctx, src.GetName(), metav1.DeleteOptions{}); err != nil {
-			if isNotFoundError(err) {
-				return nil
-			}
-			return fmt.Errorf("delete resource: %s", err)
-		}
-		return nil
+		s.forgetOwned(key)
+		return s.propagateDeletion(key, src)
 	}
 	dst := dstObj.(*unstructured.Unstructured).DeepCopy()
-
-	// Copy full status or subtree from src to dst.
-	if s.subtree == "" {
-		dst.Object["status"] = src.Object["status"]
-	} else if src.Object["status"] != nil {
-		srcStatus, ok := src.Object["status"].(map[string]interface{})
-		if !ok {
-			return fmt.Errorf("expected status of %s in downstream cluster to be a dict", src.GetName())
-		}
-		if dst.Object["status"] == nil {
-			dst.Object["status"] = make(map[string]interface{})
-		}
-		dstStatus, ok := dst.Object["status"].(map[string]interface{})
-		if !ok {
-			return fmt.Errorf("expected status of %s in upstream cluster to be a dict", src.GetName())
+	if s.upstreamOwned.differ(src, dst) {
+		// Fields owned by upstream were changed downstream, let
+		// syncUpstream() revert them.
+		s.upstreamQueue.Add(key)
+	}
+	// Replay status changes that weren't synced yet, eg while the cloud
+	// was unreachable, before copying the current status.
+	journaled := int64(-1)
+	if s.journal != nil {
+		if dst, journaled, err = s.replayJournal(key, src, dst); err != nil {
+			return err
 		}
-		if srcStatus[s.subtree] != nil {
-			dstStatus[s.subtree] = srcStatus[s.subtree]
-		} else {
-			delete(dstStatus, s.subtree)
+	}
+	hasOwned := len(s.downstreamOwned.paths) > 0
+	if s.cfg.ignoreStatus && !hasOwned {
+		return nil
+	}
+	conflicts := s.downstreamOwned.conflicts(key, src, dst)
+	owned := s.downstreamOwned.get(src)
+	if err := s.downstreamOwned.set(dst, owned); err != nil {
+		return newAPIErrorf(dst, "failed to copy owned fields: %s", err)
+	}
+	if !s.cfg.ignoreStatus {
+		if err := s.copyStatus(src, dst); err != nil {
+			return err
 		}
 	}
 	setAnnotation(dst, annotationResourceVersion, src.GetResourceVersion())
 
 	// We need to make a dedicated UpdateStatus call if the status is defined
-	// as an explicit subresource of the CRD.
-	if statusIsSubresource {
-		// Status must not be null/nil.
-		if dst.Object["status"] == nil {
-			dst.Object["status"] = struct{}{}
-		}
-		updated, err := s.upstream.UpdateStatus(s.ctx, dst, metav1.UpdateOptions{})
+	// as an explicit subresource of the CRD. Owned fields are outside of
+	// the status and need a regular update then.
+	if !s.cfg.statusSubresource || hasOwned {
+		status := dst.Object["status"]
+		updated, err := s.upstream.Update(s.ctx, dst, metav1.UpdateOptions{})
 		if err != nil {
 			// Count subsequent conflict errors
 			if k8serrors.IsConflict(err) && s.clusterName != cloudClusterName {
 				s.conflictErrors += 1
 			}
-			return newAPIErrorf(dst, "update status failed: %s", err)
+			return newAPIErrorf(dst, "update failed: %s", err)
+		}
+		if hasOwned {
+			s.downstreamOwned.written(key, owned)
+			s.reportConflicts(s.localObject(dst, src), s.downstreamSide(), conflicts)
 		}
 		dst = updated
-	} else {
-		updated, err := s.upstream.Update(s.ctx, dst, metav1.UpdateOptions{})
+		if s.cfg.statusSubresource {
+			dst.Object["status"] = status
+		}
+	}
+	if s.cfg.statusSubresource && !s.cfg.ignoreStatus {
+		// Status must not be null/nil.
+		if dst.Object["status"] == nil {
+			dst.Object["status"] = struct{}{}
+		}
+		updated, err := s.upstream.UpdateStatus(s.ctx, dst, metav1.UpdateOptions{})
 		if err != nil {
 			// Count subsequent conflict errors
 			if k8serrors.IsConflict(err) && s.clusterName != cloudClusterName {
 				s.conflictErrors += 1
 			}
-			return newAPIErrorf(dst, "update failed: %s", err)
+			return newAPIErrorf(dst, "update status failed: %s", err)
 		}
 		dst = updated
 	}
+	if journaled >= 0 {
+		if err := s.journal.ack(key, journaled); err != nil {
+			log.Printf("Failed to update status journal of %s %s: %s", src.GetKind(), src.GetName(), err)
+		}
+	}
 	// Reset error count
 	if s.clusterName != cloudClusterName {
 		s.conflictErrors = 0
@@ -502,6 +937,122 @@ func (s *crSyncer) syncDownstream(key string) error {
 	return nil
 }
 
+// replayJournal writes the journaled status changes of the downstream
+// resource src to the upstream resource dst in order. The last change is
+// skipped if it's the current status, which syncDownstream() writes anyway.
+// It returns the updated dst and the sequence number of the last journal
+// entry, or -1 if the journal is empty.
+func (s *crSyncer) replayJournal(key string, src, dst *unstructured.Unstructured) (*unstructured.Unstructured, int64, error) {
+	entries, dropped, err := s.journal.pending(key)
+	if err != nil {
+		return nil, -1, fmt.Errorf("failed to read status journal of %s: %s", key, err)
+	}
+	if len(entries) == 0 {
+		return dst, -1, nil
+	}
+	if dropped > 0 {
+		log.Printf("Dropped %d status changes of %s %s as the journal was full", dropped, src.GetKind(), src.GetName())
+	}
+	current, err := json.Marshal(src.Object["status"])
+	if err != nil {
+		return nil, -1, err
+	}
+	for i, e := range entries {
+		if i == len(entries)-1 && bytes.Equal(e.Status, current) {
+			break
+		}
+		status, err := e.status()
+		if err != nil {
+			return nil, -1, fmt.Errorf("invalid status journal of %s: %s", key, err)
+		}
+		old := &unstructured.Unstructured{Object: map[string]interface{}{"status": status}}
+		old.SetName(src.GetName())
+		if err := s.copyStatus(old, dst); err != nil {
+			return nil, -1, err
+		}
+		setAnnotation(dst, annotationResourceVersion, e.ResourceVersion)
+		updated, err := s.updateUpstreamStatus(dst)
+		if err != nil {
+			return nil, -1, newAPIErrorf(dst, "replaying status@v%s failed: %s", e.ResourceVersion, err)
+		}
+		if err := s.journal.ack(key, e.Seq); err != nil {
+			log.Printf("Failed to update status journal of %s %s: %s", src.GetKind(), src.GetName(), err)
+		}
+		log.Printf("Replayed %s %s status@v%s to upstream@v%s",
+			src.GetKind(), src.GetName(), e.ResourceVersion, updated.GetResourceVersion())
+		dst = updated
+	}
+	return dst, entries[len(entries)-1].Seq, nil
+}
+
+// updateUpstreamStatus writes the status of dst to the upstream cluster.
+func (s *crSyncer) updateUpstreamStatus(dst *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	var updated *unstructured.Unstructured
+	var err error
+	if s.cfg.statusSubresource {
+		// Status must not be null/nil.
+		if dst.Object["status"] == nil {
+			dst.Object["status"] = struct{}{}
+		}
+		updated, err = s.upstream.UpdateStatus(s.ctx, dst, metav1.UpdateOptions{})
+	} else {
+		updated, err = s.upstream.Update(s.ctx, dst, metav1.UpdateOptions{})
+	}
+	// Count subsequent conflict errors
+	if err != nil && k8serrors.IsConflict(err) && s.clusterName != cloudClusterName {
+		s.conflictErrors += 1
+	}
+	return updated, err
+}
+
+// forgetOwned drops the owned field values that were synced for a resource
+// whose upstream resource was deleted.
+func (s *crSyncer) forgetOwned(key string) {
+	s.upstreamOwned.forget(key)
+	s.downstreamOwned.forget(key)
+}
+
+// forgetResource drops the state that is kept for a resource whose
+// downstream resource is gone. The status journal is kept until then, as a
+// downstream resource that is orphaned or marked with a tombstone keeps its
+// status.
+func (s *crSyncer) forgetResource(key string) {
+	s.forgetOwned(key)
+	if s.journal != nil {
+		if err := s.journal.remove(key); err != nil {
+			log.Printf("Failed to remove status journal of %s: %s", key, err)
+		}
+	}
+}
+
+// copyStatus copies the full status or the status subtree from src to dst.
+func (s *crSyncer) copyStatus(src, dst *unstructured.Unstructured) error {
+	if s.subtree == "" {
+		dst.Object["status"] = src.Object["status"]
+		return nil
+	}
+	if src.Object["status"] == nil {
+		return nil
+	}
+	srcStatus, ok := src.Object["status"].(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("expected status of %s in downstream cluster to be a dict", src.GetName())
+	}
+	if dst.Object["status"] == nil {
+		dst.Object["status"] = make(map[string]interface{})
+	}
+	dstStatus, ok := dst.Object["status"].(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("expected status of %s in upstream cluster to be a dict", src.GetName())
+	}
+	if srcStatus[s.subtree] != nil {
+		dstStatus[s.subtree] = srcStatus[s.subtree]
+	} else {
+		delete(dstStatus, s.subtree)
+	}
+	return nil
+}
+
 // syncUpstream reconciles the state after receiving a change event from upstream.
 // It synchronizes the spec changes from upstream to the downstream cluster and propagates
 // deletions.
@@ -517,7 +1068,7 @@ func (s *crSyncer) syncUpstream(key string) error {
 		src = srcObj.(*unstructured.Unstructured).DeepCopy()
 		removeFinalizer(s.ctx, s.upstream, src, s.clusterName)
 	}
-	dstObj, dstExists, err := s.downstreamInf.GetIndexer().GetByKey(key)
+	dstObj, dstExists, err := s.downstreamInf.GetIndexer().GetByKey(s.downstreamKey(key))
 	if err != nil {
 		return fmt.Errorf("failed to retrieve resource for key %s: %s", key, err)
 	}
@@ -531,12 +1082,15 @@ func (s *crSyncer) syncUpstream(key string) error {
 	switch {
 	case !srcExists && !dstExists:
 		// Both deleted, nothing to do.
+		s.forgetResource(key)
 		return nil
 	case srcExists && !dstExists:
-		// Create object and set base fields.
+		// Create object and set base fields. State that was kept
+		// before belongs to a deleted resource.
+		s.forgetResource(key)
 		createOrUpdate = func(o *unstructured.Unstructured) (*unstructured.Unstructured, error) {
 			o.SetGroupVersionKind(src.GroupVersionKind())
-			o.SetNamespace(src.GetNamespace())
+			o.SetNamespace(s.downstreamNs)
 			o.SetName(src.GetName())
 			// Copy upstream status on initial creation.
 			o.Object["status"] = src.Object["status"]
@@ -549,14 +1103,9 @@ func (s *crSyncer) syncUpstream(key string) error {
 			return s.downstream.Update(s.ctx, o, metav1.UpdateOptions{})
 		}
 	case !srcExists && dstExists:
-		// Delete dst.
-		if err := s.downstream.Delete(s.ctx, dst.GetName(), metav1.DeleteOptions{}); err != nil {
-			if isNotFoundError(err) {
-				return nil
-			}
-			return newAPIErrorf(dst, "downstream delete failed: %s", err)
-		}
-		return nil
+		// Delete dst according to the deletion policy.
+		s.forgetOwned(key)
+		return s.propagateDeletion(key, dst)
 	default:
 		log.Fatalf("unhandled condition: srcExists=%t, dstExists=%t", srcExists, dstExists)
 		return nil
@@ -565,19 +1114,39 @@ func (s *crSyncer) syncUpstream(key string) error {
 	// Before creating/updating, check if deletion is in progress. This
 	// is checked separately to src/dstExists for readability (hopefully).
 	if src.GetDeletionTimestamp() != nil {
-		if err := s.downstream.Delete(s.ctx, src.GetName(), metav1.DeleteOptions{}); err != nil {
-			if isNotFoundError(err) {
-				return nil
-			}
-			return newAPIErrorf(dst, "downstream delete failed: %s", err)
+		if !dstExists {
+			return nil
 		}
-		return nil
+		return s.propagateDeletion(key, dst)
 	}
 
-	// Create/update dst with the labels+annotations+spec of src.
+	// Create/update dst with the labels+annotations+spec of src. Fields
+	// owned by downstream are kept, unless dst is created.
+	conflicts := s.upstreamOwned.conflicts(key, src, dst)
+	downstreamOwned := s.downstreamOwned.get(src)
+	if dstExists {
+		if s.downstreamOwned.differ(src, dst) {
+			// Fields owned by downstream were changed upstream, let
+			// syncDownstream() revert them.
+			s.downstreamQueue.Add(key)
+		}
+		downstreamOwned = s.downstreamOwned.get(dst)
+	}
 	dst.SetLabels(src.GetLabels())
 	dst.SetAnnotations(src.GetAnnotations())
-	dst.Object["spec"] = src.Object["spec"]
+	if *pruneFields {
+		deleteAnnotation(dst, annotationLastApplied)
+	}
+	for _, f := range s.cfg.specFields {
+		dst.Object[f] = src.Object[f]
+	}
+	if err := s.downstreamOwned.set(dst, downstreamOwned); err != nil {
+		return newAPIErrorf(dst, "failed to keep owned fields: %s", err)
+	}
+	owned := s.upstreamOwned.get(src)
+	if err := s.upstreamOwned.set(dst, owned); err != nil {
+		return newAPIErrorf(dst, "failed to copy owned fields: %s", err)
+	}
 
 	// The remote-resource-version annotation is removed from dst to
 	// prevent an infinite loop, because changing the annotation would
@@ -591,6 +1160,8 @@ func (s *crSyncer) syncUpstream(key string) error {
 		}
 		return newAPIErrorf(dst, "failed to create or update downstream: %s", err)
 	}
+	s.upstreamOwned.written(key, owned)
+	s.reportConflicts(s.localObject(src, dst), s.upstreamSide(), conflicts)
 	// Reset error count
 	if s.clusterName == cloudClusterName {
 		s.conflictErrors = 0
@@ -598,6 +1169,28 @@ func (s *crSyncer) syncUpstream(key string) error {
 	return nil
 }
 
+// upstreamSide returns "cloud" or "robot" for the upstream cluster.
+func (s *crSyncer) upstreamSide() string {
+	return s.cfg.specSource
+}
+
+// downstreamSide returns "cloud" or "robot" for the downstream cluster.
+func (s *crSyncer) downstreamSide() string {
+	if s.cfg.specSource == "robot" {
+		return "cloud"
+	}
+	return "robot"
+}
+
+// localObject returns the one of the upstream and downstream resource that
+// is in the local cluster.
+func (s *crSyncer) localObject(up, down *unstructured.Unstructured) *unstructured.Unstructured {
+	if s.cfg.specSource == "robot" {
+		return up
+	}
+	return down
+}
+
 func isNotFoundError(err error) bool {
 	status, ok := err.(*k8serrors.StatusError)
 	return ok && status.ErrStatus.Code == http.StatusNotFound
@@ -627,6 +1220,29 @@ func keyFunc(obj interface{}) (string, bool) {
 	return k, true
 }
 
+// upstreamKey translates the key of a downstream resource to the upstream
+// namespace.
+func (s *crSyncer) upstreamKey(key string) string {
+	return replaceKeyNamespace(key, s.upstreamNs)
+}
+
+// downstreamKey translates the key of an upstream resource to the
+// downstream namespace.
+func (s *crSyncer) downstreamKey(key string) string {
+	return replaceKeyNamespace(key, s.downstreamNs)
+}
+
+func replaceKeyNamespace(key, ns string) string {
+	if ns == "" {
+		return key
+	}
+	_, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		return key
+	}
+	return ns + "/" + name
+}
+
 func setAnnotation(o *unstructured.Unstructured, key, value string) {
 	annotations := o.GetAnnotations()
 	if annotations == nil {
@@ -63,6 +63,12 @@ func newFixture(t *testing.T) *fixture {
 }
 
 func (f *fixture) newCRSyncer(crd crdtypes.CustomResourceDefinition, robotName string) (*crSyncer, schema.GroupVersionResource) {
+	return f.newCRSyncerWithLabel(crd, robotName, "")
+}
+
+// newCRSyncerWithLabel is like newCRSyncer, but selects the resources of the
+// robot by robotLabel instead of the first label of the config.
+func (f *fixture) newCRSyncerWithLabel(crd crdtypes.CustomResourceDefinition, robotName, robotLabel string) (*crSyncer, schema.GroupVersionResource) {
 	gvk := schema.GroupVersionKind{
 		Group:   crd.Spec.Group,
 		Version: crd.Spec.Versions[0].Name,
@@ -92,7 +98,14 @@ func (f *fixture) newCRSyncer(crd crdtypes.CustomResourceDefinition, robotName s
 		f.remoteObjects...,
 	)
 
-	crs, err := newCRSyncer(context.Background(), crd, f.local, f.remote, robotName)
+	cfg, err := syncConfigFromCRD(crd)
+	if err != nil {
+		f.Fatal(err)
+	}
+	if robotLabel == "" {
+		robotLabel = cfg.robotLabels(robotName)[0]
+	}
+	crs, err := newCRSyncer(context.Background(), cfg, cfg.syncedNamespaces()[0], robotLabel, f.local, f.remote, robotName)
 	if err != nil {
 		f.Fatal(err)
 	}
@@ -267,6 +280,72 @@ func TestSyncClusterScopedCRUpstream_createSpec(t *testing.T) {
 	f.verifyWriteActions()
 }
 
+func TestSyncUpstream_namespaceMapping(t *testing.T) {
+	crd := testCRD(crdtypes.NamespaceScoped)
+	crd.Annotations[annotationNamespaceMapping] = "tenant-foo=default"
+	f := newFixture(t)
+
+	tcrRemote := newTestCR("resource1", "spec1", "status1")
+	tcrRemote.SetNamespace("tenant-foo")
+	f.addRemoteObjects(tcrRemote)
+
+	crs, gvr := f.newCRSyncer(crd, "cluster1")
+	defer crs.stop()
+
+	crs.startInformers()
+	if err := crs.syncUpstream("tenant-foo/resource1"); err != nil {
+		t.Fatal(err)
+	}
+	f.expectLocalActions(k8stest.NewCreateAction(gvr, "default", newTestCR("resource1", "spec1", "status1")))
+	f.verifyWriteActions()
+}
+
+func TestSyncDownstream_namespaceMapping(t *testing.T) {
+	crd := testCRD(crdtypes.NamespaceScoped)
+	crd.Annotations[annotationNamespaceMapping] = "tenant-foo=default"
+	f := newFixture(t)
+
+	var (
+		tcrLocal  = newTestCR("resource1", "spec1", "status2")
+		tcrRemote = newTestCR("resource1", "spec1", "status1")
+	)
+	tcrLocal.SetResourceVersion("123")
+	tcrRemote.SetNamespace("tenant-foo")
+	f.addLocalObjects(tcrLocal)
+	f.addRemoteObjects(tcrRemote)
+
+	crs, gvr := f.newCRSyncer(crd, "")
+	defer crs.stop()
+
+	crs.startInformers()
+	if err := crs.syncDownstream("tenant-foo/resource1"); err != nil {
+		t.Fatal(err)
+	}
+	tcrRemoteNew := newTestCR("resource1", "spec1", "status2")
+	tcrRemoteNew.SetNamespace("tenant-foo")
+	tcrRemoteNew.SetAnnotations(map[string]string{
+		annotationResourceVersion: "123",
+	})
+	f.expectRemoteActions(k8stest.NewUpdateAction(gvr, "tenant-foo", tcrRemoteNew))
+	f.verifyWriteActions()
+}
+
+func TestParseNamespaceMapping(t *testing.T) {
+	got, err := parseNamespaceMapping("tenant-foo=default, tenant-bar")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []namespaceMapping{{cloud: "tenant-foo", robot: "default"}, {cloud: "tenant-bar", robot: "tenant-bar"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("want %v, got %v", want, got)
+	}
+	for _, s := range []string{"tenant-foo=default,default", "Tenant", "a=b=c"} {
+		if _, err := parseNamespaceMapping(s); err == nil {
+			t.Errorf("expected error for %q", s)
+		}
+	}
+}
+
 func TestSyncUpstream_updateSpec(t *testing.T) {
 	crd := testCRD(crdtypes.NamespaceScoped)
 	f := newFixture(t)
@@ -541,6 +620,48 @@ func TestCRSyncer_populateWorkqueueWithFilter(t *testing.T) {
 	}
 }
 
+func TestSyncUpstream_filterByClusterName(t *testing.T) {
+	crd := testCRD(crdtypes.NamespaceScoped)
+	crd.ObjectMeta.Annotations[annotationFilterByRobotName] = "true"
+	crd.ObjectMeta.Annotations[annotationFilterByClusterName] = "true"
+
+	cfg, err := syncConfigFromCRD(crd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := cfg.robotLabels("robot-1"), []string{labelRobotName, labelClusterName}; !reflect.DeepEqual(got, want) {
+		t.Errorf("robotLabels() = %v, want %v", got, want)
+	}
+
+	f := newFixture(t)
+
+	// Resources for an edge cluster are labelled with the cluster name
+	// instead of the robot name, eg ChartAssignments of cloud charts.
+	crCorrectCluster := newTestCR("cr1", "spec1", "status1")
+	crWrongCluster := newTestCR("cr2", "spec2", "status2")
+
+	crCorrectCluster.SetLabels(map[string]string{labelClusterName: "robot-1"})
+	crWrongCluster.SetLabels(map[string]string{labelClusterName: "robot-2"})
+
+	f.addRemoteObjects(crCorrectCluster, crWrongCluster)
+
+	crs, gvr := f.newCRSyncerWithLabel(crd, "robot-1", labelClusterName)
+	defer crs.stop()
+
+	crs.startInformers()
+	for _, key := range []string{"default/cr1", "default/cr2"} {
+		if err := crs.syncUpstream(key); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tcrLocalNew := newTestCR("cr1", "spec1", "status1")
+	tcrLocalNew.SetLabels(map[string]string{labelClusterName: "robot-1"})
+
+	f.expectLocalActions(k8stest.NewCreateAction(gvr, "default", tcrLocalNew))
+	f.verifyWriteActions()
+}
+
 func channelFromQueue(t *testing.T, queue workqueue.Interface, inf cache.SharedIndexInformer) <-chan *unstructured.Unstructured {
 	ch := make(chan *unstructured.Unstructured, 1)
 	go func() {
@@ -0,0 +1,197 @@
+// Copyright 2026 The Cloud Robotics Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"log"
+	"reflect"
+	"sort"
+	"sync"
+
+	"go.opencensus.io/stats"
+	"go.opencensus.io/tag"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/tools/record"
+)
+
+// syncerSet runs a crSyncer for every resource that is configured for sync,
+// either by CRD annotations or by a SyncRule. A SyncRule takes precedence
+// over the annotations of the resource's CRD.
+type syncerSet struct {
+	ctx           context.Context
+	local, remote dynamic.Interface
+	robotName     string
+
+	crds  map[string]syncConfig // By CRD name.
+	rules map[string]syncConfig // By SyncRule name.
+	// syncers are the running syncers by resource name. There is one
+	// syncer per synced namespace and robot label. mu guards syncers for
+	// the health endpoints.
+	mu        sync.Mutex
+	syncers   map[string][]*crSyncer
+	informers *informerCache
+	// recorder records Events in the local cluster. It may be nil.
+	recorder record.EventRecorder
+}
+
+func newSyncerSet(ctx context.Context, local, remote dynamic.Interface, robotName string) *syncerSet {
+	return &syncerSet{
+		ctx:       ctx,
+		local:     local,
+		remote:    remote,
+		robotName: robotName,
+		crds:      map[string]syncConfig{},
+		rules:     map[string]syncConfig{},
+		syncers:   map[string][]*crSyncer{},
+		informers: newInformerCache(),
+	}
+}
+
+func (ss *syncerSet) handleCRD(change CrdChange) {
+	name := change.CRD.GetName()
+	delete(ss.crds, name)
+	if change.Type == watch.Added || change.Type == watch.Modified {
+		cfg, err := syncConfigFromCRD(*change.CRD)
+		if err != nil {
+			log.Printf("skipping custom resource %s: %s", name, err)
+		} else {
+			ss.crds[name] = cfg
+		}
+	}
+	ss.update()
+}
+
+func (ss *syncerSet) handleSyncRule(change SyncRuleChange) {
+	name := change.Rule.GetName()
+	delete(ss.rules, name)
+	if change.Type == watch.Added || change.Type == watch.Modified {
+		cfg, err := syncConfigFromRule(change.Rule)
+		if err != nil {
+			log.Printf("skipping SyncRule %s: %s", name, err)
+		} else {
+			ss.rules[name] = cfg
+		}
+	}
+	ss.update()
+}
+
+// wantConfigs returns the sync config for every synced resource. If
+// multiple SyncRules select a resource, the first by name is used.
+func wantConfigs(crds, rules map[string]syncConfig) map[string]syncConfig {
+	want := map[string]syncConfig{}
+	for _, cfg := range crds {
+		if cfg.specSource != "cloud" && cfg.specSource != "robot" {
+			// Synchronization is disabled.
+			continue
+		}
+		want[cfg.name] = cfg
+	}
+	var names []string
+	for name := range rules {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	fromRule := map[string]bool{}
+	for _, name := range names {
+		cfg := rules[name]
+		if fromRule[cfg.name] {
+			log.Printf("Warning: ignoring %s as %s is already synced by %s", cfg.origin, cfg.name, want[cfg.name].origin)
+			continue
+		}
+		fromRule[cfg.name] = true
+		want[cfg.name] = cfg
+	}
+	return want
+}
+
+// update stops syncers whose config changed or which are no longer wanted
+// and starts syncers for new configs. Changes of a CRD that don't affect the
+// config, e.g. of its status, leave the syncer running.
+func (ss *syncerSet) update() {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	want := wantConfigs(ss.crds, ss.rules)
+	for name, syncers := range ss.syncers {
+		cfg, ok := want[name]
+		if ok && reflect.DeepEqual(cfg, syncers[0].cfg) {
+			continue
+		}
+		if ok {
+			log.Printf("Restarting syncer for %s as its config changed", name)
+			recordSyncerRestart(name)
+		}
+		for _, s := range syncers {
+			s.stop()
+		}
+		delete(ss.syncers, name)
+	}
+	for name, cfg := range want {
+		if _, ok := ss.syncers[name]; ok {
+			continue
+		}
+		// Restarted syncers reuse the informers of their predecessor
+		// if the synced resources didn't change.
+		var (
+			syncers []*crSyncer
+			labels  = cfg.robotLabels(ss.robotName)
+			wantLen = len(cfg.syncedNamespaces()) * len(labels)
+		)
+		for _, ns := range cfg.syncedNamespaces() {
+			for _, label := range labels {
+				s, err := newCRSyncer(ss.ctx, cfg, ns, label, ss.local, ss.remote, ss.robotName)
+				if err != nil {
+					log.Printf("skipping %s: %s", cfg.origin, err)
+					break
+				}
+				s.informers = ss.informers
+				s.recorder = ss.recorder
+				syncers = append(syncers, s)
+			}
+		}
+		if len(syncers) != wantLen {
+			continue
+		}
+		ss.syncers[name] = syncers
+		for _, s := range syncers {
+			go s.run()
+		}
+	}
+}
+
+// statuses returns the state of the running syncers, sorted by name.
+func (ss *syncerSet) statuses() []syncerStatus {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	var res []syncerStatus
+	for _, syncers := range ss.syncers {
+		for _, s := range syncers {
+			res = append(res, s.status())
+		}
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].name < res[j].name })
+	return res
+}
+
+func recordSyncerRestart(name string) {
+	ctx, err := tag.New(context.Background(), tag.Insert(tagResource, name))
+	if err != nil {
+		panic(err)
+	}
+	stats.Record(ctx, mSyncerRestarts.M(1))
+}
@@ -0,0 +1,244 @@
+// Copyright 2026 The Cloud Robotics Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/tools/cache"
+)
+
+var syncRuleGVR = schema.GroupVersionResource{
+	Group:    "cr-syncer.cloudrobotics.com",
+	Version:  "v1alpha1",
+	Resource: "syncrules",
+}
+
+// syncRule configures the sync of a resource in the local cluster. Unlike
+// the CRD annotations, it can be used for resources whose definition we
+// don't own, including built-in types like ConfigMaps.
+type syncRule struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec syncRuleSpec `json:"spec"`
+}
+
+type syncRuleSpec struct {
+	// Group, Version and Resource select the synced resource. Group is
+	// empty for core types.
+	Group    string `json:"group,omitempty"`
+	Version  string `json:"version"`
+	Resource string `json:"resource"`
+	// Scope is either "Namespaced" (default) or "Cluster".
+	Scope string `json:"scope,omitempty"`
+	// SpecSource is the direction of the sync, see the spec-source
+	// annotation.
+	SpecSource string `json:"specSource"`
+	// LabelSelector and FieldSelector restrict the synced resources.
+	LabelSelector     string `json:"labelSelector,omitempty"`
+	FieldSelector     string `json:"fieldSelector,omitempty"`
+	FilterByRobotName bool   `json:"filterByRobotName,omitempty"`
+	// FilterByClusterName additionally syncs the resources labelled with
+	// the robot name as cluster-name, see the filter-by-cluster-name
+	// annotation.
+	FilterByClusterName bool `json:"filterByClusterName,omitempty"`
+	// StatusSubtree restricts the status sync to the given subtree.
+	StatusSubtree string `json:"statusSubtree,omitempty"`
+	// StatusSubresource must be set if the resource has a status
+	// subresource.
+	StatusSubresource bool `json:"statusSubresource,omitempty"`
+	// SpecFields are the top-level fields that are copied from the spec
+	// source, e.g. "data" for ConfigMaps. Defaults to "spec".
+	SpecFields []string `json:"specFields,omitempty"`
+	// IgnoreStatus disables syncing the status back to the spec source.
+	IgnoreStatus bool `json:"ignoreStatus,omitempty"`
+	// Namespaces maps namespaces in the cloud cluster to namespaces on
+	// the robot. Defaults to the namespace given by the --namespace flag.
+	Namespaces []syncRuleNamespace `json:"namespaces,omitempty"`
+	// StatusJournal is the maximum number of status changes that are
+	// journaled per resource, see the status-journal annotation.
+	StatusJournal int `json:"statusJournal,omitempty"`
+	// OwnedFields are paths of fields, eg "spec.target", that are synced
+	// from their owner regardless of the spec source. Robot-owned fields
+	// require spec source "robot".
+	OwnedFields syncRuleOwnedFields `json:"ownedFields,omitempty"`
+	// Deletion configures how deletions are propagated, see the
+	// deletion-policy annotation.
+	Deletion syncRuleDeletion `json:"deletion,omitempty"`
+}
+
+type syncRuleDeletion struct {
+	// Policy is "immediate" (default), "delay" or "orphan".
+	Policy string `json:"policy,omitempty"`
+	// GracePeriod is the delay of the "delay" policy, eg "10m".
+	GracePeriod string `json:"gracePeriod,omitempty"`
+}
+
+type syncRuleOwnedFields struct {
+	Cloud []string `json:"cloud,omitempty"`
+	Robot []string `json:"robot,omitempty"`
+}
+
+type syncRuleNamespace struct {
+	Cloud string `json:"cloud"`
+	// Robot defaults to the cloud namespace.
+	Robot string `json:"robot,omitempty"`
+}
+
+// syncConfigFromRule derives the sync config from a SyncRule.
+func syncConfigFromRule(u *unstructured.Unstructured) (syncConfig, error) {
+	var rule syncRule
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, &rule); err != nil {
+		return syncConfig{}, errors.Wrap(err, "invalid SyncRule")
+	}
+	spec := rule.Spec
+	if spec.Version == "" || spec.Resource == "" {
+		return syncConfig{}, errors.New("version and resource are required")
+	}
+	if spec.SpecSource != "cloud" && spec.SpecSource != "robot" {
+		return syncConfig{}, fmt.Errorf("unknown spec source %q", spec.SpecSource)
+	}
+	namespaced := true
+	switch spec.Scope {
+	case "", "Namespaced":
+	case "Cluster":
+		namespaced = false
+	default:
+		return syncConfig{}, fmt.Errorf("unknown scope %q", spec.Scope)
+	}
+	if _, err := labels.Parse(spec.LabelSelector); err != nil {
+		return syncConfig{}, errors.Wrap(err, "invalid label selector")
+	}
+	if _, err := fields.ParseSelector(spec.FieldSelector); err != nil {
+		return syncConfig{}, errors.Wrap(err, "invalid field selector")
+	}
+	for _, f := range spec.SpecFields {
+		switch f {
+		case "", "apiVersion", "kind", "metadata", "status":
+			return syncConfig{}, fmt.Errorf("invalid spec field %q", f)
+		}
+	}
+	var namespaces []namespaceMapping
+	for _, n := range spec.Namespaces {
+		m := namespaceMapping{cloud: n.Cloud, robot: n.Robot}
+		if m.robot == "" {
+			m.robot = m.cloud
+		}
+		namespaces = append(namespaces, m)
+	}
+	if err := validateNamespaceMappings(namespaces); err != nil {
+		return syncConfig{}, err
+	}
+	if len(namespaces) > 0 && !namespaced {
+		return syncConfig{}, errors.New("namespaces must not be set for cluster-scoped resources")
+	}
+	if err := validateOwnedFields(spec.SpecSource, spec.OwnedFields.Cloud, spec.OwnedFields.Robot); err != nil {
+		return syncConfig{}, errors.Wrap(err, "invalid owned fields")
+	}
+	if spec.StatusJournal < 0 {
+		return syncConfig{}, errors.New("statusJournal must not be negative")
+	}
+	deletionPolicy, deletionGracePeriod, err := parseDeletionPolicy(spec.Deletion.Policy, spec.Deletion.GracePeriod)
+	if err != nil {
+		return syncConfig{}, errors.Wrap(err, "invalid deletion")
+	}
+	if len(spec.SpecFields) == 0 {
+		spec.SpecFields = []string{"spec"}
+	}
+	gvr := schema.GroupVersionResource{Group: spec.Group, Version: spec.Version, Resource: spec.Resource}
+	return syncConfig{
+		name:              gvr.GroupResource().String(),
+		origin:            "SyncRule " + rule.Name,
+		gvr:               gvr,
+		namespaced:        namespaced,
+		statusSubresource: spec.StatusSubresource,
+		specSource:        spec.SpecSource,
+		statusSubtree:     spec.StatusSubtree,
+		filterByRobotName: spec.FilterByRobotName,
+		labelSelector:     spec.LabelSelector,
+		fieldSelector:     spec.FieldSelector,
+		specFields:        spec.SpecFields,
+		ignoreStatus:      spec.IgnoreStatus,
+		namespaces:        namespaces,
+		cloudOwnedFields:  spec.OwnedFields.Cloud,
+		robotOwnedFields:  spec.OwnedFields.Robot,
+		statusJournal:     spec.StatusJournal,
+
+		filterByClusterName: spec.FilterByClusterName,
+		deletionPolicy:      deletionPolicy,
+		deletionGracePeriod: deletionGracePeriod,
+	}, nil
+}
+
+type SyncRuleChange struct {
+	Type watch.EventType
+	Rule *unstructured.Unstructured
+}
+
+// streamSyncRules sends changes of SyncRules in the local cluster to rules.
+// Unlike streamCrds, it doesn't wait for the initial list as the SyncRule
+// CRD may not be installed.
+func streamSyncRules(done <-chan struct{}, client dynamic.Interface, rules chan<- SyncRuleChange) {
+	ri := client.Resource(syncRuleGVR)
+	informer := cache.NewSharedIndexInformer(
+		&cache.ListWatch{
+			ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+				return ri.List(context.Background(), options)
+			},
+			WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+				return ri.Watch(context.Background(), options)
+			},
+		},
+		&unstructured.Unstructured{},
+		resyncPeriod,
+		nil,
+	)
+	informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			rules <- SyncRuleChange{Type: watch.Added, Rule: obj.(*unstructured.Unstructured)}
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			rules <- SyncRuleChange{Type: watch.Modified, Rule: newObj.(*unstructured.Unstructured)}
+		},
+		DeleteFunc: func(obj interface{}) {
+			u, ok := obj.(*unstructured.Unstructured)
+			if !ok {
+				tombstone, ok := obj.(cache.DeletedFinalStateUnknown)
+				if !ok {
+					log.Printf("unexpected object in SyncRule deletion: %T", obj)
+					return
+				}
+				if u, ok = tombstone.Obj.(*unstructured.Unstructured); !ok {
+					log.Printf("unexpected object in SyncRule tombstone: %T", tombstone.Obj)
+					return
+				}
+			}
+			rules <- SyncRuleChange{Type: watch.Deleted, Rule: u}
+		},
+	})
+	go informer.Run(done)
+}
@@ -0,0 +1,132 @@
+// Copyright 2026 The Cloud Robotics Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	crdtypes "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func newTestSyncRule(name string, spec map[string]interface{}) *unstructured.Unstructured {
+	u := &unstructured.Unstructured{Object: map[string]interface{}{"spec": spec}}
+	u.SetAPIVersion("cr-syncer.cloudrobotics.com/v1alpha1")
+	u.SetKind("SyncRule")
+	u.SetName(name)
+	return u
+}
+
+func TestSyncConfigFromRule(t *testing.T) {
+	cfg, err := syncConfigFromRule(newTestSyncRule("configmaps", map[string]interface{}{
+		"version":       "v1",
+		"resource":      "configmaps",
+		"specSource":    "cloud",
+		"labelSelector": "sync=true",
+		"specFields":    []interface{}{"data", "binaryData"},
+		"ignoreStatus":  true,
+		"ownedFields": map[string]interface{}{
+			"cloud": []interface{}{"data.target"},
+		},
+		"deletion": map[string]interface{}{
+			"policy":      "delay",
+			"gracePeriod": "10m",
+		},
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := syncConfig{
+		name:          "configmaps",
+		origin:        "SyncRule configmaps",
+		gvr:           schema.GroupVersionResource{Version: "v1", Resource: "configmaps"},
+		namespaced:    true,
+		specSource:    "cloud",
+		labelSelector: "sync=true",
+		specFields:    []string{"data", "binaryData"},
+		ignoreStatus:  true,
+
+		cloudOwnedFields:    []string{"data.target"},
+		deletionPolicy:      deletionDelay,
+		deletionGracePeriod: 10 * time.Minute,
+	}
+	if !reflect.DeepEqual(cfg, want) {
+		t.Errorf("unexpected config\nwant: %+v\ngot:  %+v", want, cfg)
+	}
+
+	invalid := map[string]map[string]interface{}{
+		"missing-resource":   {"version": "v1", "specSource": "cloud"},
+		"unknown-source":     {"version": "v1", "resource": "configmaps", "specSource": "both"},
+		"unknown-scope":      {"version": "v1", "resource": "configmaps", "specSource": "cloud", "scope": "Global"},
+		"bad-label-selector": {"version": "v1", "resource": "configmaps", "specSource": "cloud", "labelSelector": "a=(b"},
+		"negative-journal":   {"version": "v1", "resource": "configmaps", "specSource": "cloud", "statusJournal": int64(-1)},
+		"metadata-field":     {"version": "v1", "resource": "configmaps", "specSource": "cloud", "specFields": []interface{}{"metadata"}},
+		"owned-status":       {"version": "v1", "resource": "configmaps", "specSource": "cloud", "ownedFields": map[string]interface{}{"cloud": []interface{}{"status"}}},
+		"robot-owned-cloud":  {"version": "v1", "resource": "configmaps", "specSource": "cloud", "ownedFields": map[string]interface{}{"robot": []interface{}{"data.progress"}}},
+		"unknown-deletion":   {"version": "v1", "resource": "configmaps", "specSource": "cloud", "deletion": map[string]interface{}{"policy": "never"}},
+		"missing-grace":      {"version": "v1", "resource": "configmaps", "specSource": "cloud", "deletion": map[string]interface{}{"policy": "delay"}},
+	}
+	for name, spec := range invalid {
+		t.Run(name, func(t *testing.T) {
+			if _, err := syncConfigFromRule(newTestSyncRule(name, spec)); err == nil {
+				t.Error("expected error, got none")
+			}
+		})
+	}
+}
+
+func TestWantConfigs(t *testing.T) {
+	goals, err := syncConfigFromCRD(testCRD(crdtypes.NamespaceScoped))
+	if err != nil {
+		t.Fatal(err)
+	}
+	disabledCRD := testCRD(crdtypes.NamespaceScoped)
+	disabledCRD.Name = "disabled.crds.example.com"
+	disabledCRD.Spec.Names.Plural = "disabled"
+	disabledCRD.Annotations[annotationSpecSource] = ""
+	disabled, err := syncConfigFromCRD(disabledCRD)
+	if err != nil {
+		t.Fatal(err)
+	}
+	newRule := func(name, source string) syncConfig {
+		cfg, err := syncConfigFromRule(newTestSyncRule(name, map[string]interface{}{
+			"group":      "crds.example.com",
+			"version":    "v1",
+			"resource":   "goals",
+			"specSource": source,
+		}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return cfg
+	}
+
+	want := wantConfigs(map[string]syncConfig{goals.name: goals, disabled.name: disabled}, nil)
+	if len(want) != 1 || want["goals.crds.example.com"].origin != goals.origin {
+		t.Errorf("expected only the goals CRD to be synced, got %+v", want)
+	}
+
+	rules := map[string]syncConfig{
+		"b": newRule("b", "cloud"),
+		"a": newRule("a", "robot"),
+	}
+	want = wantConfigs(map[string]syncConfig{goals.name: goals}, rules)
+	if got := want["goals.crds.example.com"]; got.origin != "SyncRule a" || got.specSource != "robot" {
+		t.Errorf("expected SyncRule a to take precedence, got %+v", got)
+	}
+}
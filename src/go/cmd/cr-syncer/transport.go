@@ -0,0 +1,179 @@
+// Copyright 2026 The Cloud Robotics Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"compress/gzip"
+	"context"
+	"io"
+	"net/http"
+	"strings"
+
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// Annotation set by `kubectl apply`, which isn't synced if --prune-fields is
+// set.
+const annotationLastApplied = "kubectl.kubernetes.io/last-applied-configuration"
+
+var (
+	mRemoteBytesSent = stats.Int64(
+		"cr-syncer.cloudrobotics.com/remote_bytes_sent",
+		"Bytes of request bodies sent to the remote cluster",
+		stats.UnitBytes,
+	)
+	mRemoteBytesReceived = stats.Int64(
+		"cr-syncer.cloudrobotics.com/remote_bytes_received",
+		"Bytes of response bodies received from the remote cluster, before decompression",
+		stats.UnitBytes,
+	)
+)
+
+func init() {
+	if err := view.Register(
+		&view.View{
+			Name:        "cr-syncer.cloudrobotics.com/remote_bytes_sent_total",
+			Description: "Total bytes of request bodies sent to the remote cluster",
+			Measure:     mRemoteBytesSent,
+			TagKeys:     []tag.Key{tagResource},
+			Aggregation: view.Sum(),
+		},
+		&view.View{
+			Name:        "cr-syncer.cloudrobotics.com/remote_bytes_received_total",
+			Description: "Total bytes of response bodies received from the remote cluster, before decompression",
+			Measure:     mRemoteBytesReceived,
+			TagKeys:     []tag.Key{tagResource},
+			Aggregation: view.Sum(),
+		},
+	); err != nil {
+		panic(err)
+	}
+}
+
+// meteredTransport counts the bytes sent to and received from the remote
+// cluster by resource. If compress is set, it requests gzip-compressed
+// responses and decompresses them itself, as the Go transport would
+// otherwise decompress them transparently before they can be counted.
+//
+// Note that the apiserver only compresses large responses, eg of LIST
+// requests, and that custom resources don't support protobuf, so that the
+// dynamic client always uses JSON.
+type meteredTransport struct {
+	base     http.RoundTripper
+	compress bool
+}
+
+func (t *meteredTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx, err := tag.New(context.Background(), tag.Insert(tagResource, resourceFromPath(req.URL.Path)))
+	if err != nil {
+		panic(err)
+	}
+	req = req.Clone(req.Context())
+	if req.Body != nil {
+		req.Body = &countingReader{ReadCloser: req.Body, ctx: ctx, m: mRemoteBytesSent}
+	}
+	compress := t.compress && req.Header.Get("Accept-Encoding") == ""
+	if compress {
+		req.Header.Set("Accept-Encoding", "gzip")
+	}
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = &countingReader{ReadCloser: resp.Body, ctx: ctx, m: mRemoteBytesReceived}
+	if compress && resp.Header.Get("Content-Encoding") == "gzip" {
+		resp.Body = &gzipReader{body: resp.Body}
+		resp.Header.Del("Content-Encoding")
+		resp.Header.Del("Content-Length")
+		resp.ContentLength = -1
+		resp.Uncompressed = true
+	}
+	return resp, nil
+}
+
+// resourceFromPath returns the resource that is accessed by an API request
+// as <resource>.<group>, eg "robots.registry.cloudrobotics.com", or "other"
+// for requests that don't access a resource.
+func resourceFromPath(path string) string {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	var group string
+	var rest []string
+	switch {
+	case len(segments) >= 3 && segments[0] == "api":
+		rest = segments[2:]
+	case len(segments) >= 4 && segments[0] == "apis":
+		group, rest = segments[1], segments[3:]
+	default:
+		return "other"
+	}
+	resource := rest[0]
+	if resource == "namespaces" && len(rest) >= 3 {
+		resource = rest[2]
+	}
+	if group == "" {
+		return resource
+	}
+	return resource + "." + group
+}
+
+// countingReader records the bytes read from a request or response body.
+type countingReader struct {
+	io.ReadCloser
+	ctx context.Context
+	m   *stats.Int64Measure
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	if n > 0 {
+		stats.Record(r.ctx, r.m.M(int64(n)))
+	}
+	return n, err
+}
+
+// gzipReader decompresses a response body. The gzip header is only read on
+// the first read to not block on streaming responses.
+type gzipReader struct {
+	body io.ReadCloser
+	zr   *gzip.Reader
+}
+
+func (r *gzipReader) Read(p []byte) (int, error) {
+	if r.zr == nil {
+		zr, err := gzip.NewReader(r.body)
+		if err != nil {
+			return 0, err
+		}
+		r.zr = zr
+	}
+	return r.zr.Read(p)
+}
+
+func (r *gzipReader) Close() error {
+	return r.body.Close()
+}
+
+// pruneObject drops the managed fields of obj, which are often larger than
+// the rest of the object and aren't synced. Updates without managed fields
+// leave them unchanged. The apiserver can't omit them from list and watch
+// responses, so this reduces memory, not transfer.
+func pruneObject(obj *unstructured.Unstructured) {
+	if *pruneFields {
+		obj.SetManagedFields(nil)
+	}
+}
@@ -0,0 +1,127 @@
+// Copyright 2026 The Cloud Robotics Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.opencensus.io/stats/view"
+	crdtypes "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	k8stest "k8s.io/client-go/testing"
+)
+
+func TestResourceFromPath(t *testing.T) {
+	tests := map[string]string{
+		"/api/v1/namespaces/default/configmaps/foo":                                            "configmaps",
+		"/api/v1/namespaces/default":                                                           "namespaces",
+		"/apis/registry.cloudrobotics.com/v1alpha1/robots":                                     "robots.registry.cloudrobotics.com",
+		"/apis/apps.cloudrobotics.com/v1alpha1/namespaces/default/chartassignments/foo/status": "chartassignments.apps.cloudrobotics.com",
+		"/apis":    "other",
+		"/version": "other",
+	}
+	for path, want := range tests {
+		if got := resourceFromPath(path); got != want {
+			t.Errorf("resourceFromPath(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
+
+// viewSum returns the sum recorded by the view for the given resource.
+func viewSum(t *testing.T, name, resource string) int64 {
+	t.Helper()
+	rows, err := view.RetrieveData(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range rows {
+		for _, tag := range r.Tags {
+			if tag.Key == tagResource && tag.Value == resource {
+				return int64(r.Data.(*view.SumData).Value)
+			}
+		}
+	}
+	return 0
+}
+
+func TestMeteredTransport(t *testing.T) {
+	body := strings.Repeat(`{"kind":"GoalList"}`, 100)
+	var compressed bytes.Buffer
+	zw := gzip.NewWriter(&compressed)
+	zw.Write([]byte(body))
+	zw.Close()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ioutil.ReadAll(r.Body)
+		if r.Header.Get("Accept-Encoding") != "gzip" {
+			w.Write([]byte(body))
+			return
+		}
+		w.Header().Set("Content-Encoding", "gzip")
+		w.Write(compressed.Bytes())
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: &meteredTransport{base: http.DefaultTransport, compress: true}}
+	resp, err := client.Post(server.URL+"/apis/metering.example.com/v1/goals", "application/json", strings.NewReader("request"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != body {
+		t.Errorf("unexpected response body %q", got)
+	}
+
+	if got := viewSum(t, "cr-syncer.cloudrobotics.com/remote_bytes_sent_total", "goals.metering.example.com"); got != int64(len("request")) {
+		t.Errorf("expected %d bytes sent, got %d", len("request"), got)
+	}
+	if got, want := viewSum(t, "cr-syncer.cloudrobotics.com/remote_bytes_received_total", "goals.metering.example.com"), int64(compressed.Len()); got != want {
+		t.Errorf("expected %d compressed bytes received, got %d", want, got)
+	}
+}
+
+func TestSyncUpstream_prunesLastAppliedAnnotation(t *testing.T) {
+	crd := testCRD(crdtypes.NamespaceScoped)
+	f := newFixture(t)
+
+	tcrRemote := newTestCR("resource1", "spec1", "status1")
+	tcrRemote.SetAnnotations(map[string]string{
+		annotationLastApplied: `{"spec":"spec1"}`,
+		"foo":                 "bar",
+	})
+	f.addRemoteObjects(tcrRemote)
+
+	crs, gvr := f.newCRSyncer(crd, "")
+	defer crs.stop()
+
+	crs.startInformers()
+	if err := crs.syncUpstream("default/resource1"); err != nil {
+		t.Fatal(err)
+	}
+
+	tcrLocalNew := newTestCR("resource1", "spec1", "status1")
+	tcrLocalNew.SetAnnotations(map[string]string{"foo": "bar"})
+	f.expectLocalActions(k8stest.NewCreateAction(gvr, "default", tcrLocalNew))
+	f.verifyWriteActions()
+}
@@ -47,6 +47,8 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 	scheme.AddKnownTypes(SchemeGroupVersion,
 		&App{},
 		&AppList{},
+		&AppRelease{},
+		&AppReleaseList{},
 		&AppRollout{},
 		&AppRolloutList{},
 		&ChartAssignment{},
@@ -18,6 +18,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 // +genclient
@@ -126,6 +127,81 @@ type AppSpec struct {
 	Repository string        `json:"repository"`
 	Version    string        `json:"version"`
 	Components AppComponents `json:"components"`
+	// NetworkIsolation is applied to all ChartAssignments generated for
+	// the app. Its dependencies are not set on the App, but derived from
+	// Dependencies.
+	NetworkIsolation *NetworkIsolation `json:"networkIsolation,omitempty"`
+	// Dependencies are apps that must be ready on a cluster before the
+	// app's ChartAssignment for the cluster is created or updated. Pods of
+	// an isolated app may connect to the namespaces of its dependencies.
+	Dependencies []AppDependency `json:"dependencies,omitempty"`
+}
+
+type AppDependency struct {
+	// Name of the App that is depended on.
+	Name string `json:"name"`
+	// Version is a semantic version constraint for the dependency's chart,
+	// e.g. ">=1.2.0, <2.0.0".
+	Version string `json:"version,omitempty"`
+}
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// AppRelease maps named channels of an app, e.g. "staging" and "production",
+// to app versions. AppRollouts in the same namespace can reference a channel
+// instead of a literal version. Channels are promoted by updating their
+// version or with the apps.cloudrobotics.com/promote-version and
+// promote-channel annotations, and promotions are recorded in the status.
+type AppRelease struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// +optional
+	Spec AppReleaseSpec `json:"spec,omitempty"`
+	// +optional
+	Status AppReleaseStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+type AppReleaseList struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []AppRelease `json:"items"`
+}
+
+type AppReleaseSpec struct {
+	AppName  string              `json:"appName"`
+	Channels []AppReleaseChannel `json:"channels,omitempty"`
+}
+
+type AppReleaseChannel struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type AppReleaseStatus struct {
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Channels are the channel versions as last observed by the controller.
+	Channels []AppReleaseChannel `json:"channels,omitempty"`
+	// History lists the promotions of channels, most recent first.
+	History []AppReleasePromotion `json:"history,omitempty"`
+	// PromotionError is set if the last promotion requested by the
+	// promote-version or promote-channel annotation failed.
+	PromotionError string `json:"promotionError,omitempty"`
+}
+
+// AppReleasePromotion records that a channel was moved to a new version.
+type AppReleasePromotion struct {
+	Channel string `json:"channel"`
+	Version string `json:"version"`
+	// PreviousVersion is empty if the channel was added.
+	PreviousVersion string      `json:"previousVersion,omitempty"`
+	Time            metav1.Time `json:"time"`
 }
 
 type AppComponents struct {
@@ -166,17 +242,108 @@ type AppRolloutSpec struct {
 	AppName string                `json:"appName,omitempty"`
 	Cloud   AppRolloutSpecCloud   `json:"cloud,omitempty"`
 	Robots  []AppRolloutSpecRobot `json:"robots,omitempty"`
+	// Strategy controls how changes are rolled out to robots. If unset,
+	// all robots are updated at once.
+	Strategy *AppRolloutStrategy `json:"strategy,omitempty"`
+	// Rollback optionally reverts to the last ready revision if too many
+	// ChartAssignments fail.
+	Rollback *AppRolloutRollback `json:"rollback,omitempty"`
+	// RevisionHistoryLimit is the number of revisions that are kept.
+	// Defaults to 10.
+	RevisionHistoryLimit *int32 `json:"revisionHistoryLimit,omitempty"`
+	// Gates hold back changes to robot ChartAssignments until the robot
+	// may be disturbed.
+	Gates *AppRolloutGates `json:"gates,omitempty"`
+	// ReleaseName is the AppRelease in the rollout's namespace whose
+	// channels are referenced by the rollout.
+	ReleaseName string `json:"releaseName,omitempty"`
+	// Channel of the release that determines the app version. Robots may
+	// override it with their own version or channel.
+	Channel string `json:"channel,omitempty"`
+}
+
+// AppRolloutGates define when robot ChartAssignments may be created or
+// updated. Robots with the do-not-disturb annotation are always held back.
+type AppRolloutGates struct {
+	// MaintenanceWindows restrict changes to the given time windows. If
+	// empty, changes are not restricted in time.
+	MaintenanceWindows []MaintenanceWindow `json:"maintenanceWindows,omitempty"`
+	// RobotStates are the states a robot has to report to be updated, e.g.
+	// AVAILABLE. If empty, the state is not checked. Robots whose emergency
+	// stop is pressed are held back if set.
+	RobotStates []string `json:"robotStates,omitempty"`
+}
+
+// MaintenanceWindow is a recurring time window.
+type MaintenanceWindow struct {
+	// Schedule is a cron expression with five fields (minute, hour, day of
+	// month, month, day of week) at which the window opens.
+	Schedule string `json:"schedule"`
+	// Duration is how long the window stays open, e.g. "2h".
+	Duration metav1.Duration `json:"duration"`
+	// TimeZone is the IANA name of the schedule's time zone. Defaults
+	// to UTC.
+	TimeZone string `json:"timeZone,omitempty"`
+}
+
+// AppRolloutRollback configures automatic rollbacks.
+type AppRolloutRollback struct {
+	// FailureThreshold is the number or percentage of failed
+	// ChartAssignments that may be exceeded before rolling back. Defaults
+	// to 0.
+	FailureThreshold *intstr.IntOrString `json:"failureThreshold,omitempty"`
+}
+
+// AppRolloutStrategy rolls out changes to robots in batches. The next batch
+// is only started once the previous batch is ready.
+type AppRolloutStrategy struct {
+	// BatchSize is the number or percentage of robots updated at once,
+	// e.g. 5 or "10%". Defaults to 1.
+	BatchSize *intstr.IntOrString `json:"batchSize,omitempty"`
+	// PauseSeconds is the time to wait after a batch became ready before
+	// starting the next one.
+	PauseSeconds int64 `json:"pauseSeconds,omitempty"`
+	// MaxUnavailable is the number or percentage of robots of a batch that
+	// may not be ready yet when the next batch is started. Defaults to 0.
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+	// MaxFailed is the number or percentage of updated robots that may fail
+	// before the rollout is aborted. Defaults to 0.
+	MaxFailed *intstr.IntOrString `json:"maxFailed,omitempty"`
+	// Paused stops the rollout from starting new batches.
+	Paused bool `json:"paused,omitempty"`
 }
 
 type AppRolloutSpecCloud struct {
 	Values ConfigValues `json:"values,omitempty"`
+	// Clusters are the names of the clusters the cloud component is
+	// deployed to, e.g. regional edge clusters. Defaults to the central
+	// "cloud" cluster if neither Clusters nor ClusterSelector is set.
+	Clusters []string `json:"clusters,omitempty"`
+	// ClusterSelector additionally selects registered clusters by the
+	// labels of their Robot resource. A cluster must not also be selected
+	// as a robot of an app with a robot component.
+	ClusterSelector *metav1.LabelSelector `json:"clusterSelector,omitempty"`
 }
 
 type AppRolloutSpecRobot struct {
 	Selector *RobotSelector `json:"selector,omitempty"`
 
-	Values  ConfigValues `json:"values,omitempty"`
-	Version string       `json:"version,omitempty"`
+	Values ConfigValues `json:"values,omitempty"`
+	// ValuesTemplate is a Go template that is rendered for each selected
+	// robot and yields YAML values that are merged over Values. The robot
+	// is available as .Robot with the fields Name, Type, Labels, and
+	// Annotations. The sprig functions are available, except for those
+	// that read the environment or don't render repeatably, eg now.
+	ValuesTemplate string `json:"valuesTemplate,omitempty"`
+	Version        string `json:"version,omitempty"`
+	// Channel of the rollout's release that determines the chart version
+	// for the selected robots. It must not be set along with Version.
+	Channel string `json:"channel,omitempty"`
+	// Priority decides which entry applies to a robot that is selected by
+	// multiple entries. The entry with the highest priority wins. Among
+	// entries of equal priority the most specific selector wins, i.e. the
+	// one with the most label requirements, and then the first entry.
+	Priority int32 `json:"priority,omitempty"`
 }
 
 type RobotSelector struct {
@@ -192,8 +359,135 @@ type AppRolloutStatus struct {
 	SettledAssignments int64                 `json:"settledAssignments"`
 	ReadyAssignments   int64                 `json:"readyAssignments"`
 	FailedAssignments  int64                 `json:"failedAssignments"`
+	// Rollout tracks the progress of a rollout with a strategy.
+	Rollout *AppRolloutProgress `json:"rollout,omitempty"`
+	// CurrentRevision is the revision of the app and rollout spec.
+	CurrentRevision string `json:"currentRevision,omitempty"`
+	// ReadyRevision is the last revision for which all ChartAssignments
+	// were ready.
+	ReadyRevision string `json:"readyRevision,omitempty"`
+	// RolledBackRevision is set if CurrentRevision was rolled back to
+	// ReadyRevision. It is cleared once the spec changes.
+	RolledBackRevision string `json:"rolledBackRevision,omitempty"`
+	// HeldRobots have pending changes that are held back by the gates. The
+	// list is bounded and OmittedHeldRobots counts the robots that were left
+	// out.
+	HeldRobots        []AppRolloutHeldRobot `json:"heldRobots,omitempty"`
+	OmittedHeldRobots int64                 `json:"omittedHeldRobots,omitempty"`
+	// RobotSelections lists the selected robots along with the entry of
+	// spec.robots that applies to them. The list is bounded and
+	// OmittedRobotSelections counts the robots that were left out.
+	RobotSelections        []AppRolloutRobotSelection `json:"robotSelections,omitempty"`
+	OmittedRobotSelections int64                      `json:"omittedRobotSelections,omitempty"`
+	// Robots summarizes the ChartAssignments of robots. Failed robots are
+	// listed first, followed by robots that are not ready yet. The list is
+	// bounded and OmittedRobots counts the robots that were left out.
+	Robots        []AppRolloutRobotStatus `json:"robots,omitempty"`
+	OmittedRobots int64                   `json:"omittedRobots,omitempty"`
+	// Preview is set while the rollout is annotated for a dry run. It lists
+	// the changes to ChartAssignments that would be applied without it.
+	Preview *AppRolloutPreview `json:"preview,omitempty"`
+}
+
+type AppRolloutPreview struct {
+	// Revision is the revision of the previewed app and rollout spec.
+	Revision  string `json:"revision,omitempty"`
+	Create    int64  `json:"create"`
+	Update    int64  `json:"update"`
+	Delete    int64  `json:"delete"`
+	Unchanged int64  `json:"unchanged"`
+	// Changes lists the ChartAssignments that would be created, updated or
+	// deleted. The list is bounded and OmittedChanges counts the changes
+	// that were left out.
+	Changes        []AppRolloutPreviewChange `json:"changes,omitempty"`
+	OmittedChanges int64                     `json:"omittedChanges,omitempty"`
+}
+
+type AppRolloutPreviewChange struct {
+	// Name is the name of the ChartAssignment.
+	Name   string                  `json:"name"`
+	Robot  string                  `json:"robot,omitempty"`
+	Action AppRolloutPreviewAction `json:"action"`
+	// Diff lists the changed fields of the ChartAssignment's labels,
+	// annotations and spec, including the chart values.
+	Diff        []AppRolloutPreviewField `json:"diff,omitempty"`
+	OmittedDiff int64                    `json:"omittedDiff,omitempty"`
+}
+
+type AppRolloutPreviewAction string
+
+const (
+	AppRolloutPreviewActionCreate AppRolloutPreviewAction = "Create"
+	AppRolloutPreviewActionUpdate AppRolloutPreviewAction = "Update"
+	AppRolloutPreviewActionDelete AppRolloutPreviewAction = "Delete"
+)
+
+// AppRolloutPreviewField is a changed field. Old and New are JSON-encoded and
+// empty if the field is added or removed.
+type AppRolloutPreviewField struct {
+	Path string `json:"path"`
+	Old  string `json:"old,omitempty"`
+	New  string `json:"new,omitempty"`
+}
+
+type AppRolloutRobotStatus struct {
+	Name    string               `json:"name"`
+	Phase   ChartAssignmentPhase `json:"phase,omitempty"`
+	Version string               `json:"version,omitempty"`
+	// LastTransitionTime is the latest transition of the ChartAssignment's
+	// conditions.
+	LastTransitionTime *metav1.Time `json:"lastTransitionTime,omitempty"`
+	// Message is a shortened error message if the ChartAssignment is not
+	// settled or ready.
+	Message string `json:"message,omitempty"`
+}
+
+type AppRolloutRobotSelection struct {
+	Name string `json:"name"`
+	// Entry is the index of the spec.robots entry that applies to the robot.
+	Entry int32 `json:"entry"`
+	// Matched are the indices of all entries that select the robot.
+	Matched []int32 `json:"matched"`
+}
+
+type AppRolloutHeldRobot struct {
+	Name   string `json:"name"`
+	Reason string `json:"reason"`
+}
+
+type AppRolloutProgress struct {
+	// Revision identifies the app and rollout spec that is rolled out.
+	Revision string                  `json:"revision,omitempty"`
+	Phase    AppRolloutProgressPhase `json:"phase,omitempty"`
+	Message  string                  `json:"message,omitempty"`
+	// Batch is the number of batches started for the revision.
+	Batch int64 `json:"batch,omitempty"`
+	// BatchRobots are the robots updated in the current batch.
+	BatchRobots    []string     `json:"batchRobots,omitempty"`
+	BatchStartTime *metav1.Time `json:"batchStartTime,omitempty"`
+	// BatchReadyTime is set once all robots of the current batch are ready.
+	BatchReadyTime *metav1.Time `json:"batchReadyTime,omitempty"`
+	TotalRobots    int64        `json:"totalRobots"`
+	UpdatedRobots  int64        `json:"updatedRobots"`
+	ReadyRobots    int64        `json:"readyRobots"`
+	FailedRobots   int64        `json:"failedRobots"`
 }
 
+type AppRolloutProgressPhase string
+
+const (
+	// Progressing is set while batches are being rolled out.
+	AppRolloutProgressPhaseProgressing AppRolloutProgressPhase = "Progressing"
+	// Paused is set while waiting between batches or if the strategy is
+	// paused.
+	AppRolloutProgressPhasePaused AppRolloutProgressPhase = "Paused"
+	// Aborted is set when too many robots failed. No further batches are
+	// started until the revision changes.
+	AppRolloutProgressPhaseAborted AppRolloutProgressPhase = "Aborted"
+	// Completed is set when all robots have been updated.
+	AppRolloutProgressPhaseCompleted AppRolloutProgressPhase = "Completed"
+)
+
 type AppRolloutCondition struct {
 	Type               AppRolloutConditionType `json:"type"`
 	Status             corev1.ConditionStatus  `json:"status"`
@@ -207,6 +501,14 @@ type AppRolloutConditionType string
 const (
 	AppRolloutConditionSettled AppRolloutConditionType = "Settled"
 	AppRolloutConditionReady   AppRolloutConditionType = "Ready"
+	// RolledBack is true if the current revision was rolled back.
+	AppRolloutConditionRolledBack AppRolloutConditionType = "RolledBack"
+	// RobotsFailed is true if ChartAssignments of robots failed. The
+	// message names the first failed robots.
+	AppRolloutConditionRobotsFailed AppRolloutConditionType = "RobotsFailed"
+	// DependenciesReady is false if ChartAssignments are held back because
+	// the app's dependencies aren't ready on their clusters.
+	AppRolloutConditionDependenciesReady AppRolloutConditionType = "DependenciesReady"
 )
 
 // +genclient
@@ -237,8 +539,46 @@ type ChartAssignmentSpec struct {
 	ClusterName   string        `json:"clusterName"`
 	NamespaceName string        `json:"namespaceName"`
 	Chart         AssignedChart `json:"chart"`
+	// Resources optionally bounds the compute resources that can be
+	// consumed in the namespace.
+	Resources *ChartAssignmentResources `json:"resources,omitempty"`
+	// NetworkIsolation optionally restricts network traffic of pods in
+	// the namespace.
+	NetworkIsolation *NetworkIsolation `json:"networkIsolation,omitempty"`
 }
 
+// ChartAssignmentResources are materialized as a ResourceQuota and a
+// LimitRange in the namespace of the ChartAssignment.
+type ChartAssignmentResources struct {
+	// Quota is the hard limit for the namespace, e.g. for requests.cpu,
+	// limits.memory, or pods.
+	Quota corev1.ResourceList `json:"quota,omitempty"`
+	// DefaultLimits are set on containers that don't specify limits.
+	DefaultLimits corev1.ResourceList `json:"defaultLimits,omitempty"`
+	// DefaultRequests are set on containers that don't specify requests.
+	DefaultRequests corev1.ResourceList `json:"defaultRequests,omitempty"`
+}
+
+// NetworkIsolation is materialized as NetworkPolicies in the namespace of
+// a ChartAssignment.
+type NetworkIsolation struct {
+	// Mode selects the isolation level. Defaults to None.
+	Mode NetworkIsolationMode `json:"mode,omitempty"`
+	// Dependencies are the names of other apps whose namespaces pods may
+	// connect to on the same cluster.
+	Dependencies []string `json:"dependencies,omitempty"`
+}
+
+type NetworkIsolationMode string
+
+const (
+	// None leaves the namespace open to all traffic.
+	NetworkIsolationNone NetworkIsolationMode = "None"
+	// Namespace denies all traffic except within the namespace, to DNS,
+	// to the metadata-server, and to the namespaces of dependencies.
+	NetworkIsolationNamespace NetworkIsolationMode = "Namespace"
+)
+
 type AssignedChart struct {
 	Repository string       `json:"repository,omitempty"`
 	Name       string       `json:"name,omitempty"`
@@ -259,6 +599,13 @@ type ChartAssignmentStatus struct {
 	ObservedGeneration int64                      `json:"observedGeneration,omitempty"`
 	Phase              ChartAssignmentPhase       `json:"phase,omitempty"`
 	Conditions         []ChartAssignmentCondition `json:"conditions,omitempty"`
+	// Quota is the hard limit and current usage of the namespace's
+	// ResourceQuota if spec.resources.quota is set.
+	Quota *corev1.ResourceQuotaStatus `json:"quota,omitempty"`
+	// ObservedSpecHash is the hash of the spec the status refers to. Unlike
+	// ObservedGeneration, it is the same for a ChartAssignment in the cloud
+	// and its copy on the robot, whose status is synced to the cloud.
+	ObservedSpecHash string `json:"observedSpecHash,omitempty"`
 }
 
 type ChartAssignmentPhase string
@@ -20,8 +20,10 @@
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
+	intstr "k8s.io/apimachinery/pkg/util/intstr"
 )
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
@@ -29,7 +31,7 @@ func (in *App) DeepCopyInto(out *App) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
 	return
 }
 
@@ -85,6 +87,22 @@ func (in *AppComponents) DeepCopy() *AppComponents {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AppDependency) DeepCopyInto(out *AppDependency) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AppDependency.
+func (in *AppDependency) DeepCopy() *AppDependency {
+	if in == nil {
+		return nil
+	}
+	out := new(AppDependency)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AppList) DeepCopyInto(out *AppList) {
 	*out = *in
@@ -118,6 +136,149 @@ func (in *AppList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AppRelease) DeepCopyInto(out *AppRelease) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AppRelease.
+func (in *AppRelease) DeepCopy() *AppRelease {
+	if in == nil {
+		return nil
+	}
+	out := new(AppRelease)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AppRelease) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AppReleaseChannel) DeepCopyInto(out *AppReleaseChannel) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AppReleaseChannel.
+func (in *AppReleaseChannel) DeepCopy() *AppReleaseChannel {
+	if in == nil {
+		return nil
+	}
+	out := new(AppReleaseChannel)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AppReleaseList) DeepCopyInto(out *AppReleaseList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]AppRelease, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AppReleaseList.
+func (in *AppReleaseList) DeepCopy() *AppReleaseList {
+	if in == nil {
+		return nil
+	}
+	out := new(AppReleaseList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AppReleaseList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AppReleasePromotion) DeepCopyInto(out *AppReleasePromotion) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AppReleasePromotion.
+func (in *AppReleasePromotion) DeepCopy() *AppReleasePromotion {
+	if in == nil {
+		return nil
+	}
+	out := new(AppReleasePromotion)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AppReleaseSpec) DeepCopyInto(out *AppReleaseSpec) {
+	*out = *in
+	if in.Channels != nil {
+		in, out := &in.Channels, &out.Channels
+		*out = make([]AppReleaseChannel, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AppReleaseSpec.
+func (in *AppReleaseSpec) DeepCopy() *AppReleaseSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AppReleaseSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AppReleaseStatus) DeepCopyInto(out *AppReleaseStatus) {
+	*out = *in
+	if in.Channels != nil {
+		in, out := &in.Channels, &out.Channels
+		*out = make([]AppReleaseChannel, len(*in))
+		copy(*out, *in)
+	}
+	if in.History != nil {
+		in, out := &in.History, &out.History
+		*out = make([]AppReleasePromotion, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AppReleaseStatus.
+func (in *AppReleaseStatus) DeepCopy() *AppReleaseStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AppReleaseStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AppRollout) DeepCopyInto(out *AppRollout) {
 	*out = *in
@@ -164,6 +325,48 @@ func (in *AppRolloutCondition) DeepCopy() *AppRolloutCondition {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AppRolloutGates) DeepCopyInto(out *AppRolloutGates) {
+	*out = *in
+	if in.MaintenanceWindows != nil {
+		in, out := &in.MaintenanceWindows, &out.MaintenanceWindows
+		*out = make([]MaintenanceWindow, len(*in))
+		copy(*out, *in)
+	}
+	if in.RobotStates != nil {
+		in, out := &in.RobotStates, &out.RobotStates
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AppRolloutGates.
+func (in *AppRolloutGates) DeepCopy() *AppRolloutGates {
+	if in == nil {
+		return nil
+	}
+	out := new(AppRolloutGates)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AppRolloutHeldRobot) DeepCopyInto(out *AppRolloutHeldRobot) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AppRolloutHeldRobot.
+func (in *AppRolloutHeldRobot) DeepCopy() *AppRolloutHeldRobot {
+	if in == nil {
+		return nil
+	}
+	out := new(AppRolloutHeldRobot)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AppRolloutList) DeepCopyInto(out *AppRolloutList) {
 	*out = *in
@@ -197,6 +400,157 @@ func (in *AppRolloutList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AppRolloutPreview) DeepCopyInto(out *AppRolloutPreview) {
+	*out = *in
+	if in.Changes != nil {
+		in, out := &in.Changes, &out.Changes
+		*out = make([]AppRolloutPreviewChange, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AppRolloutPreview.
+func (in *AppRolloutPreview) DeepCopy() *AppRolloutPreview {
+	if in == nil {
+		return nil
+	}
+	out := new(AppRolloutPreview)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AppRolloutPreviewChange) DeepCopyInto(out *AppRolloutPreviewChange) {
+	*out = *in
+	if in.Diff != nil {
+		in, out := &in.Diff, &out.Diff
+		*out = make([]AppRolloutPreviewField, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AppRolloutPreviewChange.
+func (in *AppRolloutPreviewChange) DeepCopy() *AppRolloutPreviewChange {
+	if in == nil {
+		return nil
+	}
+	out := new(AppRolloutPreviewChange)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AppRolloutPreviewField) DeepCopyInto(out *AppRolloutPreviewField) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AppRolloutPreviewField.
+func (in *AppRolloutPreviewField) DeepCopy() *AppRolloutPreviewField {
+	if in == nil {
+		return nil
+	}
+	out := new(AppRolloutPreviewField)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AppRolloutProgress) DeepCopyInto(out *AppRolloutProgress) {
+	*out = *in
+	if in.BatchRobots != nil {
+		in, out := &in.BatchRobots, &out.BatchRobots
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.BatchStartTime != nil {
+		in, out := &in.BatchStartTime, &out.BatchStartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.BatchReadyTime != nil {
+		in, out := &in.BatchReadyTime, &out.BatchReadyTime
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AppRolloutProgress.
+func (in *AppRolloutProgress) DeepCopy() *AppRolloutProgress {
+	if in == nil {
+		return nil
+	}
+	out := new(AppRolloutProgress)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AppRolloutRollback) DeepCopyInto(out *AppRolloutRollback) {
+	*out = *in
+	if in.FailureThreshold != nil {
+		in, out := &in.FailureThreshold, &out.FailureThreshold
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AppRolloutRollback.
+func (in *AppRolloutRollback) DeepCopy() *AppRolloutRollback {
+	if in == nil {
+		return nil
+	}
+	out := new(AppRolloutRollback)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AppRolloutRobotSelection) DeepCopyInto(out *AppRolloutRobotSelection) {
+	*out = *in
+	if in.Matched != nil {
+		in, out := &in.Matched, &out.Matched
+		*out = make([]int32, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AppRolloutRobotSelection.
+func (in *AppRolloutRobotSelection) DeepCopy() *AppRolloutRobotSelection {
+	if in == nil {
+		return nil
+	}
+	out := new(AppRolloutRobotSelection)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AppRolloutRobotStatus) DeepCopyInto(out *AppRolloutRobotStatus) {
+	*out = *in
+	if in.LastTransitionTime != nil {
+		in, out := &in.LastTransitionTime, &out.LastTransitionTime
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AppRolloutRobotStatus.
+func (in *AppRolloutRobotStatus) DeepCopy() *AppRolloutRobotStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AppRolloutRobotStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AppRolloutSpec) DeepCopyInto(out *AppRolloutSpec) {
 	*out = *in
@@ -208,6 +562,26 @@ func (in *AppRolloutSpec) DeepCopyInto(out *AppRolloutSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Strategy != nil {
+		in, out := &in.Strategy, &out.Strategy
+		*out = new(AppRolloutStrategy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Rollback != nil {
+		in, out := &in.Rollback, &out.Rollback
+		*out = new(AppRolloutRollback)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RevisionHistoryLimit != nil {
+		in, out := &in.RevisionHistoryLimit, &out.RevisionHistoryLimit
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Gates != nil {
+		in, out := &in.Gates, &out.Gates
+		*out = new(AppRolloutGates)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -225,6 +599,16 @@ func (in *AppRolloutSpec) DeepCopy() *AppRolloutSpec {
 func (in *AppRolloutSpecCloud) DeepCopyInto(out *AppRolloutSpecCloud) {
 	*out = *in
 	out.Values = in.Values.DeepCopy()
+	if in.Clusters != nil {
+		in, out := &in.Clusters, &out.Clusters
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ClusterSelector != nil {
+		in, out := &in.ClusterSelector, &out.ClusterSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -270,6 +654,35 @@ func (in *AppRolloutStatus) DeepCopyInto(out *AppRolloutStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Rollout != nil {
+		in, out := &in.Rollout, &out.Rollout
+		*out = new(AppRolloutProgress)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HeldRobots != nil {
+		in, out := &in.HeldRobots, &out.HeldRobots
+		*out = make([]AppRolloutHeldRobot, len(*in))
+		copy(*out, *in)
+	}
+	if in.RobotSelections != nil {
+		in, out := &in.RobotSelections, &out.RobotSelections
+		*out = make([]AppRolloutRobotSelection, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Robots != nil {
+		in, out := &in.Robots, &out.Robots
+		*out = make([]AppRolloutRobotStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Preview != nil {
+		in, out := &in.Preview, &out.Preview
+		*out = new(AppRolloutPreview)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -283,10 +696,51 @@ func (in *AppRolloutStatus) DeepCopy() *AppRolloutStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AppRolloutStrategy) DeepCopyInto(out *AppRolloutStrategy) {
+	*out = *in
+	if in.BatchSize != nil {
+		in, out := &in.BatchSize, &out.BatchSize
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	if in.MaxUnavailable != nil {
+		in, out := &in.MaxUnavailable, &out.MaxUnavailable
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	if in.MaxFailed != nil {
+		in, out := &in.MaxFailed, &out.MaxFailed
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AppRolloutStrategy.
+func (in *AppRolloutStrategy) DeepCopy() *AppRolloutStrategy {
+	if in == nil {
+		return nil
+	}
+	out := new(AppRolloutStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AppSpec) DeepCopyInto(out *AppSpec) {
 	*out = *in
 	out.Components = in.Components
+	if in.NetworkIsolation != nil {
+		in, out := &in.NetworkIsolation, &out.NetworkIsolation
+		*out = new(NetworkIsolation)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Dependencies != nil {
+		in, out := &in.Dependencies, &out.Dependencies
+		*out = make([]AppDependency, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -396,10 +850,57 @@ func (in *ChartAssignmentList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChartAssignmentResources) DeepCopyInto(out *ChartAssignmentResources) {
+	*out = *in
+	if in.Quota != nil {
+		in, out := &in.Quota, &out.Quota
+		*out = make(corev1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	if in.DefaultLimits != nil {
+		in, out := &in.DefaultLimits, &out.DefaultLimits
+		*out = make(corev1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	if in.DefaultRequests != nil {
+		in, out := &in.DefaultRequests, &out.DefaultRequests
+		*out = make(corev1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChartAssignmentResources.
+func (in *ChartAssignmentResources) DeepCopy() *ChartAssignmentResources {
+	if in == nil {
+		return nil
+	}
+	out := new(ChartAssignmentResources)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ChartAssignmentSpec) DeepCopyInto(out *ChartAssignmentSpec) {
 	*out = *in
 	in.Chart.DeepCopyInto(&out.Chart)
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(ChartAssignmentResources)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NetworkIsolation != nil {
+		in, out := &in.NetworkIsolation, &out.NetworkIsolation
+		*out = new(NetworkIsolation)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -423,6 +924,11 @@ func (in *ChartAssignmentStatus) DeepCopyInto(out *ChartAssignmentStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Quota != nil {
+		in, out := &in.Quota, &out.Quota
+		*out = new(corev1.ResourceQuotaStatus)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -445,6 +951,44 @@ func (in ConfigValues) DeepCopyInto(out *ConfigValues) {
 	}
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaintenanceWindow) DeepCopyInto(out *MaintenanceWindow) {
+	*out = *in
+	out.Duration = in.Duration
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenanceWindow.
+func (in *MaintenanceWindow) DeepCopy() *MaintenanceWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(MaintenanceWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkIsolation) DeepCopyInto(out *NetworkIsolation) {
+	*out = *in
+	if in.Dependencies != nil {
+		in, out := &in.Dependencies, &out.Dependencies
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkIsolation.
+func (in *NetworkIsolation) DeepCopy() *NetworkIsolation {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkIsolation)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ResourceRef) DeepCopyInto(out *ResourceRef) {
 	*out = *in
@@ -24,6 +24,8 @@ import (
 type Interface interface {
 	// Apps returns a AppInformer.
 	Apps() AppInformer
+	// AppReleases returns a AppReleaseInformer.
+	AppReleases() AppReleaseInformer
 	// AppRollouts returns a AppRolloutInformer.
 	AppRollouts() AppRolloutInformer
 	// ChartAssignments returns a ChartAssignmentInformer.
@@ -48,6 +50,11 @@ func (v *version) Apps() AppInformer {
 	return &appInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
 }
 
+// AppReleases returns a AppReleaseInformer.
+func (v *version) AppReleases() AppReleaseInformer {
+	return &appReleaseInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
 // AppRollouts returns a AppRolloutInformer.
 func (v *version) AppRollouts() AppRolloutInformer {
 	return &appRolloutInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
@@ -56,6 +56,8 @@ func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource
 	// Group=apps.cloudrobotics.com, Version=v1alpha1
 	case v1alpha1.SchemeGroupVersion.WithResource("apps"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Apps().V1alpha1().Apps().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("appreleases"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Apps().V1alpha1().AppReleases().Informer()}, nil
 	case v1alpha1.SchemeGroupVersion.WithResource("approllouts"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Apps().V1alpha1().AppRollouts().Informer()}, nil
 	case v1alpha1.SchemeGroupVersion.WithResource("chartassignments"):
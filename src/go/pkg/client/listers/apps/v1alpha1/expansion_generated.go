@@ -20,6 +20,14 @@ package v1alpha1
 // AppLister.
 type AppListerExpansion interface{}
 
+// AppReleaseListerExpansion allows custom methods to be added to
+// AppReleaseLister.
+type AppReleaseListerExpansion interface{}
+
+// AppReleaseNamespaceListerExpansion allows custom methods to be added to
+// AppReleaseNamespaceLister.
+type AppReleaseNamespaceListerExpansion interface{}
+
 // AppRolloutListerExpansion allows custom methods to be added to
 // AppRolloutLister.
 type AppRolloutListerExpansion interface{}
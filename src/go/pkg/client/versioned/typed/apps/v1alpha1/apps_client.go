@@ -25,6 +25,7 @@ import (
 type AppsV1alpha1Interface interface {
 	RESTClient() rest.Interface
 	AppsGetter
+	AppReleasesGetter
 	AppRolloutsGetter
 	ChartAssignmentsGetter
 	ResourceSetsGetter
@@ -39,6 +40,10 @@ func (c *AppsV1alpha1Client) Apps() AppInterface {
 	return newApps(c)
 }
 
+func (c *AppsV1alpha1Client) AppReleases(namespace string) AppReleaseInterface {
+	return newAppReleases(c, namespace)
+}
+
 func (c *AppsV1alpha1Client) AppRollouts(namespace string) AppRolloutInterface {
 	return newAppRollouts(c, namespace)
 }
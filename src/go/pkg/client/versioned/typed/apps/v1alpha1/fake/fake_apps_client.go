@@ -30,6 +30,10 @@ func (c *FakeAppsV1alpha1) Apps() v1alpha1.AppInterface {
 	return &FakeApps{c}
 }
 
+func (c *FakeAppsV1alpha1) AppReleases(namespace string) v1alpha1.AppReleaseInterface {
+	return &FakeAppReleases{c, namespace}
+}
+
 func (c *FakeAppsV1alpha1) AppRollouts(namespace string) v1alpha1.AppRolloutInterface {
 	return &FakeAppRollouts{c, namespace}
 }
@@ -18,6 +18,8 @@ package v1alpha1
 
 type AppExpansion interface{}
 
+type AppReleaseExpansion interface{}
+
 type AppRolloutExpansion interface{}
 
 type ChartAssignmentExpansion interface{}
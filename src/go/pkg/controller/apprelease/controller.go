@@ -0,0 +1,129 @@
+// Copyright 2026 The Cloud Robotics Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package apprelease records promotions of AppRelease channels and promotes
+// channels on request, either by annotating the AppRelease or with the
+// Promote and PromoteChannel functions.
+package apprelease
+
+import (
+	"context"
+	"log"
+	"time"
+
+	apps "github.com/SAP/cloud-robotics/src/go/pkg/apis/apps/v1alpha1"
+	"github.com/pkg/errors"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+// maxHistory is the number of promotions kept in the status.
+const maxHistory = 20
+
+// Add adds a controller for the AppRelease resource type to the manager.
+func Add(mgr manager.Manager) error {
+	r := &Reconciler{kube: mgr.GetClient()}
+
+	c, err := controller.New("apprelease", mgr, controller.Options{
+		Reconciler: r,
+	})
+	if err != nil {
+		return errors.Wrap(err, "create controller")
+	}
+	err = c.Watch(
+		&source.Kind{Type: &apps.AppRelease{}},
+		&handler.EnqueueRequestForObject{},
+	)
+	if err != nil {
+		return errors.Wrap(err, "watch AppReleases")
+	}
+	return nil
+}
+
+// Reconciler records channel promotions of AppReleases in their status.
+type Reconciler struct {
+	kube kclient.Client
+}
+
+func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	var rel apps.AppRelease
+	err := r.kube.Get(ctx, req.NamespacedName, &rel)
+
+	if k8serrors.IsNotFound(err) {
+		return reconcile.Result{}, nil
+	} else if err != nil {
+		return reconcile.Result{}, errors.Wrapf(err, "get AppRelease %q", req)
+	}
+	requestChanged := false
+	if promotionRequested(&rel) {
+		updated, reqErr, err := applyPromotionRequests(ctx, r.kube, req.NamespacedName)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		rel = *updated
+		msg := ""
+		if reqErr != nil {
+			log.Printf("AppRelease %s/%s: promotion failed: %s", rel.Namespace, rel.Name, reqErr)
+			msg = reqErr.Error()
+		}
+		requestChanged = msg != rel.Status.PromotionError
+		rel.Status.PromotionError = msg
+	}
+	if !requestChanged && rel.Status.ObservedGeneration == rel.Generation && rel.Generation != 0 {
+		return reconcile.Result{}, nil
+	}
+	for _, p := range recordPromotions(&rel, time.Now()) {
+		log.Printf("AppRelease %s/%s: promoted channel %q from %q to %q",
+			rel.Namespace, rel.Name, p.Channel, p.PreviousVersion, p.Version)
+	}
+	if err := r.kube.Status().Update(ctx, &rel); err != nil {
+		return reconcile.Result{}, errors.Wrap(err, "update status")
+	}
+	return reconcile.Result{}, nil
+}
+
+// recordPromotions compares the channels in the spec with those last
+// observed, prepends the promotions to the history, and returns them.
+func recordPromotions(rel *apps.AppRelease, now time.Time) []apps.AppReleasePromotion {
+	observed := map[string]string{}
+	for _, c := range rel.Status.Channels {
+		observed[c.Name] = c.Version
+	}
+	var promotions []apps.AppReleasePromotion
+	for _, c := range rel.Spec.Channels {
+		prev, ok := observed[c.Name]
+		if ok && prev == c.Version {
+			continue
+		}
+		promotions = append(promotions, apps.AppReleasePromotion{
+			Channel:         c.Name,
+			Version:         c.Version,
+			PreviousVersion: prev,
+			Time:            metav1.Time{Time: now},
+		})
+	}
+	rel.Status.History = append(promotions, rel.Status.History...)
+	if len(rel.Status.History) > maxHistory {
+		rel.Status.History = rel.Status.History[:maxHistory]
+	}
+	rel.Status.Channels = append([]apps.AppReleaseChannel(nil), rel.Spec.Channels...)
+	rel.Status.ObservedGeneration = rel.Generation
+	return promotions
+}
@@ -0,0 +1,161 @@
+// Copyright 2026 The Cloud Robotics Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apprelease
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	apps "github.com/SAP/cloud-robotics/src/go/pkg/apis/apps/v1alpha1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestRecordPromotions(t *testing.T) {
+	now := time.Now()
+	rel := &apps.AppRelease{}
+	rel.Spec.Channels = []apps.AppReleaseChannel{
+		{Name: "staging", Version: "1.4.0"},
+		{Name: "production", Version: "1.3.0"},
+	}
+	if got := recordPromotions(rel, now); len(got) != 2 {
+		t.Fatalf("expected new channels to be recorded, got %+v", got)
+	}
+	// Unchanged channels are not recorded again.
+	if got := recordPromotions(rel, now); len(got) != 0 {
+		t.Fatalf("expected no promotions, got %+v", got)
+	}
+	rel.Spec.Channels[1].Version = "1.4.0"
+	got := recordPromotions(rel, now)
+	if len(got) != 1 {
+		t.Fatalf("expected one promotion, got %+v", got)
+	}
+	want := apps.AppReleasePromotion{Channel: "production", Version: "1.4.0", PreviousVersion: "1.3.0"}
+	if p := rel.Status.History[0]; p.Channel != want.Channel || p.Version != want.Version || p.PreviousVersion != want.PreviousVersion {
+		t.Errorf("expected latest promotion %+v first, got %+v", want, p)
+	}
+	if len(rel.Status.History) != 3 {
+		t.Errorf("expected 3 history entries, got %d", len(rel.Status.History))
+	}
+
+	for i := 0; i < 2*maxHistory; i++ {
+		rel.Spec.Channels[0].Version = time.Duration(i).String()
+		recordPromotions(rel, now)
+	}
+	if len(rel.Status.History) != maxHistory {
+		t.Errorf("expected history to be limited to %d, got %d", maxHistory, len(rel.Status.History))
+	}
+}
+
+func TestPromoteChannel(t *testing.T) {
+	ctx := context.Background()
+	sc := runtime.NewScheme()
+	scheme.AddToScheme(sc)
+	apps.AddToScheme(sc)
+
+	rel := &apps.AppRelease{}
+	rel.Namespace = "default"
+	rel.Name = "foo"
+	rel.Spec.AppName = "foo"
+	rel.Spec.Channels = []apps.AppReleaseChannel{{Name: "staging", Version: "1.4.0"}}
+	kube := fake.NewClientBuilder().WithScheme(sc).WithObjects(rel).Build()
+	r := &Reconciler{kube: kube}
+	key := kclient.ObjectKeyFromObject(rel)
+
+	if _, err := update(ctx, kube, key, promoteVersion("production", "1.3.0")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: key}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := update(ctx, kube, key, promoteChannel("staging", "production")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: key}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := update(ctx, kube, key, promoteChannel("canary", "production")); err == nil {
+		t.Error("expected error for unknown source channel")
+	}
+
+	var got apps.AppRelease
+	if err := kube.Get(ctx, key, &got); err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := ChannelVersion(&got, "production"); v != "1.4.0" {
+		t.Errorf("expected production to be promoted to 1.4.0, got %q", v)
+	}
+	if p := got.Status.History[0]; p.Channel != "production" || p.Version != "1.4.0" || p.PreviousVersion != "1.3.0" {
+		t.Errorf("unexpected latest promotion %+v", p)
+	}
+}
+
+func TestReconcile_promotionAnnotations(t *testing.T) {
+	ctx := context.Background()
+	sc := runtime.NewScheme()
+	scheme.AddToScheme(sc)
+	apps.AddToScheme(sc)
+
+	rel := &apps.AppRelease{}
+	rel.Namespace = "default"
+	rel.Name = "foo"
+	rel.Spec.AppName = "foo"
+	rel.Spec.Channels = []apps.AppReleaseChannel{{Name: "staging", Version: "1.4.0"}}
+	rel.Annotations = map[string]string{annotationPromoteChannel: "production=staging"}
+	kube := fake.NewClientBuilder().WithScheme(sc).WithObjects(rel).Build()
+	r := &Reconciler{kube: kube}
+	key := kclient.ObjectKeyFromObject(rel)
+
+	if _, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: key}); err != nil {
+		t.Fatal(err)
+	}
+	var got apps.AppRelease
+	if err := kube.Get(ctx, key, &got); err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := ChannelVersion(&got, "production"); v != "1.4.0" {
+		t.Errorf("expected production to be promoted to 1.4.0, got %q", v)
+	}
+	if promotionRequested(&got) {
+		t.Errorf("expected promotion annotation to be removed, got %v", got.Annotations)
+	}
+	// The promotion of production is recorded along with the initial
+	// version of staging.
+	if h := got.Status.History; len(h) != 2 || h[1].Channel != "production" || h[1].Version != "1.4.0" {
+		t.Errorf("unexpected promotion history %+v", h)
+	}
+
+	// Invalid requests are dropped and reported in the status.
+	got.Annotations = map[string]string{annotationPromoteChannel: "production=canary"}
+	if err := kube.Update(ctx, &got); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: key}); err != nil {
+		t.Fatal(err)
+	}
+	if err := kube.Get(ctx, key, &got); err != nil {
+		t.Fatal(err)
+	}
+	if promotionRequested(&got) || got.Status.PromotionError == "" {
+		t.Errorf("expected dropped request and promotion error, got annotations %v and status %+v", got.Annotations, got.Status)
+	}
+	if v, _ := ChannelVersion(&got, "production"); v != "1.4.0" {
+		t.Errorf("expected production to stay at 1.4.0, got %q", v)
+	}
+}
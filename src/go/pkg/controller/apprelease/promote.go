@@ -0,0 +1,163 @@
+// Copyright 2026 The Cloud Robotics Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apprelease
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	apps "github.com/SAP/cloud-robotics/src/go/pkg/apis/apps/v1alpha1"
+	"github.com/cenkalti/backoff"
+	"github.com/pkg/errors"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Annotations that request a promotion of an AppRelease. Both have the form
+// "<channel>=<source>", eg
+//
+//	kubectl annotate apprelease foo apps.cloudrobotics.com/promote-channel=production=staging
+//
+// The controller applies the promotion and removes the annotation in a single
+// update of the AppRelease.
+const (
+	// annotationPromoteVersion moves the channel to the given version, see
+	// Promote.
+	annotationPromoteVersion = "apps.cloudrobotics.com/promote-version"
+	// annotationPromoteChannel moves the channel to the version of the
+	// given source channel, see PromoteChannel.
+	annotationPromoteChannel = "apps.cloudrobotics.com/promote-channel"
+)
+
+// ChannelVersion returns the version of the named channel.
+func ChannelVersion(rel *apps.AppRelease, channel string) (string, bool) {
+	for _, c := range rel.Spec.Channels {
+		if c.Name == channel {
+			return c.Version, true
+		}
+	}
+	return "", false
+}
+
+func setChannelVersion(rel *apps.AppRelease, channel, version string) {
+	for i, c := range rel.Spec.Channels {
+		if c.Name == channel {
+			rel.Spec.Channels[i].Version = version
+			return
+		}
+	}
+	rel.Spec.Channels = append(rel.Spec.Channels, apps.AppReleaseChannel{Name: channel, Version: version})
+}
+
+// promoteVersion moves the channel of the AppRelease to the given version.
+// The channel is added if it doesn't exist yet.
+func promoteVersion(channel, version string) func(*apps.AppRelease) error {
+	return func(rel *apps.AppRelease) error {
+		setChannelVersion(rel, channel, version)
+		return nil
+	}
+}
+
+// promoteChannel moves channel to to the current version of channel from,
+// e.g. to promote the version tested in "staging" to "production". The
+// version is read and written in a single update of the AppRelease, so a
+// concurrent promotion of from is never partially applied.
+func promoteChannel(from, to string) func(*apps.AppRelease) error {
+	return func(rel *apps.AppRelease) error {
+		version, ok := ChannelVersion(rel, from)
+		if !ok {
+			return errors.Errorf("channel %q not found", from)
+		}
+		setChannelVersion(rel, to, version)
+		return nil
+	}
+}
+
+// promotionRequested returns true if the AppRelease has a promote-version or
+// promote-channel annotation.
+func promotionRequested(rel *apps.AppRelease) bool {
+	_, v := rel.Annotations[annotationPromoteVersion]
+	_, c := rel.Annotations[annotationPromoteChannel]
+	return v || c
+}
+
+// parsePromotion parses the "<channel>=<source>" value of a promotion
+// annotation.
+func parsePromotion(annotation, value string) (string, string, error) {
+	parts := strings.SplitN(value, "=", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("invalid %s annotation %q, want <channel>=<source>", annotation, value)
+	}
+	return parts[0], parts[1], nil
+}
+
+// applyPromotionRequests promotes the channels requested by annotations and
+// removes the annotations in the same update. Invalid requests are dropped
+// and returned as reqErr, err is set if the update failed.
+func applyPromotionRequests(ctx context.Context, kube kclient.Client, key kclient.ObjectKey) (rel *apps.AppRelease, reqErr error, err error) {
+	rel, err = update(ctx, kube, key, func(rel *apps.AppRelease) error {
+		reqErr = nil
+		if v, ok := rel.Annotations[annotationPromoteVersion]; ok {
+			delete(rel.Annotations, annotationPromoteVersion)
+			if channel, version, err := parsePromotion(annotationPromoteVersion, v); err != nil {
+				reqErr = err
+			} else {
+				promoteVersion(channel, version)(rel)
+			}
+		}
+		if v, ok := rel.Annotations[annotationPromoteChannel]; ok {
+			delete(rel.Annotations, annotationPromoteChannel)
+			to, from, err := parsePromotion(annotationPromoteChannel, v)
+			if err == nil {
+				err = promoteChannel(from, to)(rel)
+			}
+			if err != nil {
+				reqErr = err
+			}
+		}
+		return nil
+	})
+	return rel, reqErr, err
+}
+
+// update applies f to the AppRelease, retries on conflicts and returns the
+// updated AppRelease.
+func update(ctx context.Context, kube kclient.Client, key kclient.ObjectKey, f func(*apps.AppRelease) error) (*apps.AppRelease, error) {
+	var rel apps.AppRelease
+	b := backoff.WithMaxRetries(backoff.NewExponentialBackOff(), 5)
+	err := backoff.Retry(func() error {
+		rel = apps.AppRelease{}
+		if err := kube.Get(ctx, key, &rel); err != nil {
+			return backoff.Permanent(errors.Wrapf(err, "get AppRelease %s", key))
+		}
+		if err := f(&rel); err != nil {
+			return backoff.Permanent(err)
+		}
+		err := kube.Update(ctx, &rel)
+		if err == nil {
+			return nil
+		}
+		if k8serrors.IsConflict(err) {
+			// Retry conflicts.
+			return err
+		}
+		return backoff.Permanent(errors.Wrapf(err, "update AppRelease %s", key))
+	}, b)
+	if err != nil {
+		return nil, err
+	}
+	return &rel, nil
+}
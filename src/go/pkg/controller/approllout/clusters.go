@@ -0,0 +1,123 @@
+// Copyright 2026 The Cloud Robotics Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package approllout
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	apps "github.com/SAP/cloud-robotics/src/go/pkg/apis/apps/v1alpha1"
+	registry "github.com/SAP/cloud-robotics/src/go/pkg/apis/registry/v1alpha1"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/api/validation"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// cloudClusterName is the name of the central cloud cluster.
+const cloudClusterName = "cloud"
+
+// cloudClusters returns the clusters the cloud component is deployed to.
+// The cluster selector must have been resolved with resolveCloudClusters.
+func cloudClusters(spec *apps.AppRolloutSpecCloud) []string {
+	if len(spec.Clusters) == 0 && spec.ClusterSelector == nil {
+		return []string{cloudClusterName}
+	}
+	return spec.Clusters
+}
+
+// resolveCloudClusters adds the registered clusters that are selected by
+// the cloud cluster selector to the rollout's cloud clusters. Clusters are
+// sorted and deduplicated.
+func resolveCloudClusters(ctx context.Context, kube kclient.Client, ar *apps.AppRollout) error {
+	cloud := &ar.Spec.Cloud
+	if cloud.ClusterSelector == nil {
+		return nil
+	}
+	sel, err := metav1.LabelSelectorAsSelector(cloud.ClusterSelector)
+	if err != nil {
+		return errors.Wrap(err, "cluster selector")
+	}
+	var robots registry.RobotList
+	err = kube.List(ctx, &robots, kclient.InNamespace(ar.Namespace), kclient.MatchingLabelsSelector{Selector: sel})
+	if err != nil {
+		return errors.Wrapf(err, "list Robots for cluster selector %q", sel)
+	}
+	seen := map[string]bool{}
+	clusters := []string{}
+	for _, c := range cloud.Clusters {
+		if !seen[c] {
+			seen[c] = true
+			clusters = append(clusters, c)
+		}
+	}
+	for _, r := range robots.Items {
+		if !seen[r.Name] {
+			seen[r.Name] = true
+			clusters = append(clusters, r.Name)
+		}
+	}
+	sort.Strings(clusters)
+	cloud.Clusters = clusters
+	return nil
+}
+
+// errClusterOverlap is returned if a robot is selected by the rollout and is
+// also one of its cloud clusters. Both ChartAssignments would deploy to the
+// same namespace on the same cluster.
+type errClusterOverlap struct {
+	cluster string
+}
+
+func (e errClusterOverlap) Error() string {
+	return fmt.Sprintf("robot %q is selected as robot and as cloud cluster", e.cluster)
+}
+
+// checkClusterOverlap returns an error if one of the selected robots is also
+// a cloud cluster.
+func checkClusterOverlap(clusters []string, selections []robotSelection) error {
+	robots := map[string]bool{}
+	for _, s := range selections {
+		robots[s.robot.Name] = true
+	}
+	for _, c := range clusters {
+		if robots[c] {
+			return errClusterOverlap{cluster: c}
+		}
+	}
+	return nil
+}
+
+// validateCloudClusters checks the cluster names and the cluster selector.
+func validateCloudClusters(spec *apps.AppRolloutSpecCloud) error {
+	seen := map[string]bool{}
+	for _, c := range spec.Clusters {
+		if errs := validation.ValidateClusterName(c, false); len(errs) > 0 {
+			return errors.Errorf("invalid cluster name %q: %s", c, strings.Join(errs, ", "))
+		}
+		if seen[c] {
+			return errors.Errorf("duplicate cluster %q", c)
+		}
+		seen[c] = true
+	}
+	if spec.ClusterSelector != nil {
+		if _, err := metav1.LabelSelectorAsSelector(spec.ClusterSelector); err != nil {
+			return errors.Wrap(err, "invalid cluster selector")
+		}
+	}
+	return nil
+}
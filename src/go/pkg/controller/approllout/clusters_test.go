@@ -0,0 +1,174 @@
+// Copyright 2026 The Cloud Robotics Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package approllout
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	apps "github.com/SAP/cloud-robotics/src/go/pkg/apis/apps/v1alpha1"
+	config "github.com/SAP/cloud-robotics/src/go/pkg/apis/config/v1alpha1"
+	registry "github.com/SAP/cloud-robotics/src/go/pkg/apis/registry/v1alpha1"
+)
+
+func TestGenerateChartAssignments_cloudClusters(t *testing.T) {
+	var app apps.App
+	unmarshalYAML(t, &app, `
+metadata:
+  name: foo
+spec:
+  components:
+    cloud:
+      inline: inline-cloud
+	`)
+	var rollout apps.AppRollout
+	unmarshalYAML(t, &rollout, `
+metadata:
+  name: foo-rollout
+  namespace: default
+spec:
+  appName: foo
+  cloud:
+    clusters: [cloud, warehouse-1]
+	`)
+	var tenant config.Tenant
+
+	cas, _, err := generateChartAssignments(&app, &rollout, &tenant, nil, nil)
+	if err != nil {
+		t.Fatalf("Generate failed: %s", err)
+	}
+	var got [][3]string
+	for _, ca := range cas {
+		got = append(got, [3]string{ca.Name, ca.Spec.ClusterName, ca.Labels[labelClusterName]})
+	}
+	// Only the ChartAssignment of the edge cluster is labelled for the
+	// cr-syncer of the cluster.
+	want := [][3]string{
+		{"foo-rollout-cloud", "cloud", ""},
+		{"foo-rollout-cloud-warehouse-1", "warehouse-1", "warehouse-1"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected ChartAssignments %v, got %v", want, got)
+	}
+	for _, ca := range cas {
+		if _, ok := ca.Labels[labelRobotName]; ok {
+			t.Errorf("cloud ChartAssignment %q has robot label", ca.Name)
+		}
+	}
+}
+
+func TestGenerateChartAssignments_rejectsClusterOverlap(t *testing.T) {
+	var app apps.App
+	unmarshalYAML(t, &app, `
+metadata:
+  name: foo
+spec:
+  components:
+    cloud:
+      inline: inline-cloud
+    robot:
+      inline: inline-robot
+	`)
+	var rollout apps.AppRollout
+	unmarshalYAML(t, &rollout, `
+metadata:
+  name: foo-rollout
+  namespace: default
+spec:
+  appName: foo
+  cloud:
+    clusters: [cloud, warehouse-1]
+  robots:
+  - selector:
+      any: true
+	`)
+	var tenant config.Tenant
+	var robot registry.Robot
+	unmarshalYAML(t, &robot, `
+metadata:
+  name: warehouse-1
+	`)
+
+	_, _, err := generateChartAssignments(&app, &rollout, &tenant, []registry.Robot{robot}, nil)
+	if _, ok := err.(errClusterOverlap); !ok {
+		t.Errorf("expected errClusterOverlap, got %v", err)
+	}
+}
+
+func TestResolveCloudClusters(t *testing.T) {
+	var edge1, edge2, robot registry.Robot
+	unmarshalYAML(t, &edge1, `
+metadata:
+  name: edge-2
+  namespace: default
+  labels:
+    type: edge
+	`)
+	unmarshalYAML(t, &edge2, `
+metadata:
+  name: edge-1
+  namespace: default
+  labels:
+    type: edge
+	`)
+	unmarshalYAML(t, &robot, `
+metadata:
+  name: robot1
+  namespace: default
+	`)
+	kube := newFakeClient(&edge1, &edge2, &robot)
+
+	var ar apps.AppRollout
+	unmarshalYAML(t, &ar, `
+metadata:
+  name: foo
+  namespace: default
+spec:
+  appName: foo
+  cloud:
+    clusters: [cloud, edge-1]
+    clusterSelector:
+      matchLabels:
+        type: edge
+	`)
+	if err := resolveCloudClusters(context.Background(), kube, &ar); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"cloud", "edge-1", "edge-2"}
+	if got := cloudClusters(&ar.Spec.Cloud); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected clusters %v, got %v", want, got)
+	}
+
+	// A selector that matches nothing doesn't fall back to the cloud cluster.
+	var none apps.AppRollout
+	unmarshalYAML(t, &none, `
+metadata:
+  name: foo
+  namespace: default
+spec:
+  appName: foo
+  cloud:
+    clusterSelector:
+      matchLabels:
+        type: none
+	`)
+	if err := resolveCloudClusters(context.Background(), kube, &none); err != nil {
+		t.Fatal(err)
+	}
+	if got := cloudClusters(&none.Spec.Cloud); len(got) != 0 {
+		t.Errorf("expected no clusters, got %v", got)
+	}
+}
@@ -23,11 +23,13 @@ import (
 	"reflect"
 	"sort"
 	"strings"
+	"text/template"
 	"time"
 
 	apps "github.com/SAP/cloud-robotics/src/go/pkg/apis/apps/v1alpha1"
 	config "github.com/SAP/cloud-robotics/src/go/pkg/apis/config/v1alpha1"
 	registry "github.com/SAP/cloud-robotics/src/go/pkg/apis/registry/v1alpha1"
+	"github.com/SAP/cloud-robotics/src/go/pkg/controller/chartassignment"
 	"github.com/SAP/cloud-robotics/src/go/pkg/coretools"
 	"github.com/pkg/errors"
 	core "k8s.io/api/core/v1"
@@ -55,6 +57,10 @@ const (
 	fieldIndexOwners  = "metadata.ownerReferences.uid"
 	fieldIndexAppName = "spec.appName"
 	labelRobotName    = "cloudrobotics.com/robot-name"
+	// labelClusterName marks the cloud ChartAssignments of edge clusters,
+	// which the cr-syncer of the cluster syncs to it. It's distinct from
+	// labelRobotName, which marks robot ChartAssignments.
+	labelClusterName = "cloudrobotics.com/cluster-name"
 )
 
 // Add adds a controller for the AppRollout resource type
@@ -79,6 +85,14 @@ func Add(ctx context.Context, mgr manager.Manager, baseValues chartutil.Values)
 	if err != nil {
 		return errors.Wrap(err, "add field indexer")
 	}
+	err = mgr.GetCache().IndexField(ctx, &apps.App{}, fieldIndexAppDependencies, indexAppDependencies)
+	if err != nil {
+		return errors.Wrap(err, "add field indexer")
+	}
+	err = mgr.GetCache().IndexField(ctx, &apps.AppRollout{}, fieldIndexReleaseName, indexReleaseName)
+	if err != nil {
+		return errors.Wrap(err, "add field indexer")
+	}
 
 	err = c.Watch(
 		&source.Kind{Type: &apps.AppRollout{}},
@@ -88,7 +102,8 @@ func Add(ctx context.Context, mgr manager.Manager, baseValues chartutil.Values)
 		return errors.Wrap(err, "watch AppRollouts")
 	}
 	// We don't trigger on ChartAssignment creations since it was either ourselves
-	// or a CA we don't care about anyway.
+	// or a CA we don't care about anyway. Changes of a CA are also relevant to
+	// rollouts of apps that depend on its app.
 	err = c.Watch(
 		&source.Kind{Type: &apps.ChartAssignment{}},
 		// We manually enqueue for the owner reference since handler.EnqueueRequestForOwner
@@ -98,17 +113,19 @@ func Add(ctx context.Context, mgr manager.Manager, baseValues chartutil.Values)
 		&handler.Funcs{
 			DeleteFunc: func(evt event.DeleteEvent, q workqueue.RateLimitingInterface) {
 				r.enqueueForOwner(evt.Object, q)
+				r.enqueueForDependents(ctx, evt.Object, q)
 			},
 			UpdateFunc: func(evt event.UpdateEvent, q workqueue.RateLimitingInterface) {
 				r.enqueueForOwner(evt.ObjectNew, q)
+				r.enqueueForDependents(ctx, evt.ObjectNew, q)
 			},
 		},
 	)
 	if err != nil {
 		return errors.Wrap(err, "watch ChartAssignments")
 	}
-	// Determining which rollouts are affected by a robot change is tedious.
-	// We just enqueue all AppRollouts again.
+	// Robot events only enqueue the AppRollouts in the robot's namespace that
+	// select the robot before or after the change.
 	err = c.Watch(
 		&source.Kind{Type: &registry.Robot{}},
 		// We log robot events for now while b/125308238 persists.
@@ -117,22 +134,25 @@ func Add(ctx context.Context, mgr manager.Manager, baseValues chartutil.Values)
 		&handler.Funcs{
 			CreateFunc: func(e event.CreateEvent, q workqueue.RateLimitingInterface) {
 				log.Printf("AppRollout controller received create event for Robot %s/%s", e.Object.GetNamespace(), e.Object.GetName())
-				r.enqueueAllInNamespace(ctx, e.Object.GetNamespace(), q)
+				r.enqueueForRobot(ctx, e.Object.GetNamespace(), q, e.Object.GetLabels())
 			},
 			UpdateFunc: func(e event.UpdateEvent, q workqueue.RateLimitingInterface) {
 				// Robots don't have the status subresource enabled. Filter updates that didn't
-				// change robot name or labels.
+				// change robot name, labels, or the inputs of deployment gates and values
+				// templates.
 				change := !reflect.DeepEqual(e.ObjectOld.GetLabels(), e.ObjectNew.GetLabels())
 				change = change || e.ObjectOld.GetName() != e.ObjectNew.GetName()
+				change = change || robotGateChanged(e.ObjectOld, e.ObjectNew)
+				change = change || robotTemplateInputChanged(e.ObjectOld, e.ObjectNew)
 				if change {
 					log.Printf("AppRollout controller received update event for Robot %s/%s", e.ObjectNew.GetNamespace(), e.ObjectNew.GetName())
-					r.enqueueAllInNamespace(ctx, e.ObjectNew.GetNamespace(), q)
+					r.enqueueForRobot(ctx, e.ObjectNew.GetNamespace(), q, e.ObjectOld.GetLabels(), e.ObjectNew.GetLabels())
 				}
 			},
 			DeleteFunc: func(e event.DeleteEvent, q workqueue.RateLimitingInterface) {
 				log.Printf("AppRollout controller received delete event for Robot %s/%s", e.Object.GetNamespace(), e.Object.GetName())
 				time.AfterFunc(3*time.Second, func() {
-					r.enqueueAllInNamespace(ctx, e.Object.GetNamespace(), q)
+					r.enqueueForRobot(ctx, e.Object.GetNamespace(), q, e.Object.GetLabels())
 				})
 			},
 		},
@@ -160,6 +180,28 @@ func Add(ctx context.Context, mgr manager.Manager, baseValues chartutil.Values)
 	if err != nil {
 		return errors.Wrap(err, "watch Apps")
 	}
+	// Promoting a channel of a release changes the versions of all rollouts
+	// that reference it.
+	err = c.Watch(
+		&source.Kind{Type: &apps.AppRelease{}},
+		&handler.Funcs{
+			CreateFunc: func(e event.CreateEvent, q workqueue.RateLimitingInterface) {
+				r.enqueueForRelease(ctx, e.Object, q)
+			},
+			UpdateFunc: func(e event.UpdateEvent, q workqueue.RateLimitingInterface) {
+				if e.ObjectOld.GetGeneration() != e.ObjectNew.GetGeneration() {
+					log.Printf("AppRollout controller received update event for AppRelease %s/%s", e.ObjectNew.GetNamespace(), e.ObjectNew.GetName())
+					r.enqueueForRelease(ctx, e.ObjectNew, q)
+				}
+			},
+			DeleteFunc: func(e event.DeleteEvent, q workqueue.RateLimitingInterface) {
+				r.enqueueForRelease(ctx, e.Object, q)
+			},
+		},
+	)
+	if err != nil {
+		return errors.Wrap(err, "watch AppReleases")
+	}
 	return nil
 }
 
@@ -205,21 +247,6 @@ func (r *Reconciler) enqueueAll(ctx context.Context, q workqueue.RateLimitingInt
 	}
 }
 
-// enqueueAll enqueues all AppRollouts.
-func (r *Reconciler) enqueueAllInNamespace(ctx context.Context, namespace string, q workqueue.RateLimitingInterface) {
-	var rollouts apps.AppRolloutList
-	err := r.kube.List(ctx, &rollouts, kclient.InNamespace(namespace))
-	if err != nil {
-		log.Printf("List AppRollouts failed: %s", err)
-		return
-	}
-	for _, ar := range rollouts.Items {
-		q.Add(reconcile.Request{
-			NamespacedName: types.NamespacedName{Name: ar.Name, Namespace: ar.Namespace},
-		})
-	}
-}
-
 // Reconciler provides an idempotent function that brings the cluster into a
 // state consistent with the specification of an AppRollout.
 type Reconciler struct {
@@ -247,7 +274,6 @@ func (r *Reconciler) reconcile(ctx context.Context, ar *apps.AppRollout) (reconc
 	var (
 		app    apps.App
 		curCAs apps.ChartAssignmentList
-		robots registry.RobotList
 		tenant config.Tenant
 	)
 	ar.Status.ObservedGeneration = ar.Generation
@@ -264,24 +290,41 @@ func (r *Reconciler) reconcile(ctx context.Context, ar *apps.AppRollout) (reconc
 	if err != nil {
 		return reconcile.Result{}, errors.Wrapf(err, "list ChartAssignments for owner UID %s", ar.UID)
 	}
-	tenantName := strings.TrimPrefix(ar.Namespace, coretools.TenantPrefix)
-	err = r.kube.Get(ctx, kclient.ObjectKey{Name: tenantName}, &tenant)
-	if k8serrors.IsNotFound(err) && ar.Namespace == metav1.NamespaceDefault {
-		log.Printf("AppRollout %s is in default namespace. No tenant information added", ar.Name)
-	} else if k8serrors.IsNotFound(err) {
-		return reconcile.Result{}, errors.Errorf("Tenant %s not found", tenantName)
-	} else if err != nil {
-		return reconcile.Result{}, errors.Wrap(err, "get Tenant")
+	if err := getTenant(ctx, r.kube, ar, &tenant); err != nil {
+		return reconcile.Result{}, err
 	}
-	// NOTE(freinartz): consider pushing this down to generateChartAssignments
-	// and passing the robot selectors directly to the client.
-	if err := r.kube.List(ctx, &robots, kclient.InNamespace(ar.Namespace)); err != nil {
-		return reconcile.Result{}, errors.Wrap(err, "list all Robots")
+	robots, err := listSelectedRobots(ctx, r.kube, ar)
+	if err != nil {
+		return reconcile.Result{}, err
 	}
 
-	wantCAs, err := generateChartAssignments(&app, ar, &tenant, robots.Items, r.baseValues)
+	release, err := getRelease(ctx, r.kube, ar)
 	if err != nil {
-		if _, ok := errors.Cause(err).(errRobotSelectorOverlap); ok {
+		if _, ok := err.(errRelease); ok {
+			return reconcile.Result{}, r.updateErrorStatus(ctx, ar, err.Error())
+		}
+		return reconcile.Result{}, err
+	}
+	// The app and rollout with channels resolved to versions. They are used
+	// to generate ChartAssignments while the status is written to ar.
+	resolvedApp, resolvedAr, err := resolveChannels(&app, ar, release)
+	if err != nil {
+		return reconcile.Result{}, r.updateErrorStatus(ctx, ar, err.Error())
+	}
+	// The revision is computed before resolving the cloud cluster selector
+	// so that clusters joining or leaving don't create new revisions, just
+	// as robots matching the robot selectors don't.
+	revision, data, err := rolloutRevision(resolvedApp, resolvedAr)
+	if err != nil {
+		return reconcile.Result{}, errors.Wrap(err, "compute revision")
+	}
+	if err := resolveCloudClusters(ctx, r.kube, resolvedAr); err != nil {
+		return reconcile.Result{}, err
+	}
+	wantCAs, selections, err := generateChartAssignments(resolvedApp, resolvedAr, &tenant, robots, r.baseValues)
+	if err != nil {
+		switch errors.Cause(err).(type) {
+		case errValuesTemplate, errClusterOverlap:
 			return reconcile.Result{}, r.updateErrorStatus(ctx, ar, err.Error())
 		}
 		return reconcile.Result{}, errors.Wrap(err, "generate ChartAssignments")
@@ -290,9 +333,92 @@ func (r *Reconciler) reconcile(ctx context.Context, ar *apps.AppRollout) (reconc
 	// ChartAssignments that are no longer wanted. We pre-populate it with
 	// all existing CAs and remove those that we want to keep
 	dropCAs := map[string]apps.ChartAssignment{}
+	prevCAs := map[string]apps.ChartAssignment{}
 
 	for _, ca := range curCAs.Items {
 		dropCAs[ca.Name] = ca
+		prevCAs[ca.Name] = ca
+	}
+	var robotCAs []*apps.ChartAssignment
+	for _, ca := range wantCAs {
+		if _, ok := ca.Labels[labelRobotName]; ok {
+			robotCAs = append(robotCAs, ca)
+		}
+	}
+	if isDryRun(ar) {
+		preview, err := previewChartAssignments(revision, wantCAs, curCAs.Items)
+		if err != nil {
+			return reconcile.Result{}, errors.Wrap(err, "preview ChartAssignments")
+		}
+		ar.Status.Preview = preview
+		setStatus(ar, len(curCAs.Items), curCAs.Items)
+		if err := r.kube.Status().Update(ctx, ar); err != nil {
+			return reconcile.Result{}, errors.Wrap(err, "update status")
+		}
+		return reconcile.Result{}, nil
+	}
+	ar.Status.Preview = nil
+
+	if err := r.ensureRevision(ctx, ar, revision, data); err != nil {
+		return reconcile.Result{}, errors.Wrap(err, "ensure revision")
+	}
+	rolledBack, err := updateRevisionStatus(ar, revision, wantCAs, prevCAs)
+	if err != nil {
+		return reconcile.Result{}, errors.Wrap(err, "update revision status")
+	}
+	held, heldWait, err := heldRobotChartAssignments(ar, robotCAs, prevCAs, robots, time.Now())
+	if err != nil {
+		return reconcile.Result{}, r.updateErrorStatus(ctx, ar, err.Error())
+	}
+	if err := validateDependencies(&app); err != nil {
+		return reconcile.Result{}, r.updateErrorStatus(ctx, ar, err.Error())
+	}
+	if err := r.checkDependencyCycle(ctx, &app); err != nil {
+		if _, ok := err.(errDependencyCycle); ok {
+			return reconcile.Result{}, r.updateErrorStatus(ctx, ar, err.Error())
+		}
+		return reconcile.Result{}, errors.Wrap(err, "check dependency cycle")
+	}
+	depState, err := r.getDependencyState(ctx, ar, &app)
+	if err != nil {
+		return reconcile.Result{}, errors.Wrap(err, "get dependencies")
+	}
+	depHeld, err := dependencyHeldChartAssignments(&app, depState, wantCAs, prevCAs)
+	if err != nil {
+		return reconcile.Result{}, errors.Wrap(err, "check dependencies")
+	}
+	for name, reason := range depHeld {
+		if _, ok := held[name]; !ok {
+			held[name] = reason
+		}
+	}
+	var plan *rolloutPlan
+	if rolledBack {
+		// Revert all ChartAssignments to the last ready revision at once.
+		ready, err := r.getRevision(ctx, ar, ar.Status.ReadyRevision)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		readyApp, readyAr := app.DeepCopy(), ar.DeepCopy()
+		readyApp.Spec, readyAr.Spec = ready.App, ready.Rollout
+		// The ready revision may select other robots.
+		readyRobots, err := listSelectedRobots(ctx, r.kube, readyAr)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		if err := resolveCloudClusters(ctx, r.kube, readyAr); err != nil {
+			return reconcile.Result{}, err
+		}
+		wantCAs, _, err = generateChartAssignments(readyApp, readyAr, &tenant, readyRobots, r.baseValues)
+		if err != nil {
+			return reconcile.Result{}, errors.Wrap(err, "generate ChartAssignments for ready revision")
+		}
+		plan = &rolloutPlan{all: true}
+	} else {
+		plan, err = planRollout(ar, revision, robotCAs, prevCAs, held, time.Now())
+		if err != nil {
+			return reconcile.Result{}, errors.Wrap(err, "plan rollout")
+		}
 	}
 	// Create or update ChartAssignments. Only update ChartAssignments if the rollout's
 	// spec or labels have been updated.
@@ -309,6 +435,13 @@ func (r *Reconciler) reconcile(ctx context.Context, ar *apps.AppRollout) (reconc
 		prev, exists := dropCAs[ca.Name]
 		delete(dropCAs, ca.Name)
 
+		if _, ok := ca.Labels[labelRobotName]; ok && !plan.allowed(ca.Name) {
+			// Left for a later batch of the rollout.
+			continue
+		}
+		if _, ok := held[ca.Name]; ok {
+			continue
+		}
 		if !exists {
 			if err := r.kube.Create(ctx, ca); err != nil {
 				return reconcile.Result{}, errors.Wrapf(err, "create ChartAssignment %s/%s", ca.Namespace, ca.Name)
@@ -336,11 +469,33 @@ func (r *Reconciler) reconcile(ctx context.Context, ar *apps.AppRollout) (reconc
 	}
 
 	setStatus(ar, len(wantCAs), curCAs.Items)
+	setHeldRobots(ar, robotCAs, held)
+	setDependencyCondition(ar, &app, wantCAs, depHeld)
+	setRobotSelections(ar, selections)
 
 	if err := r.kube.Status().Update(ctx, ar); err != nil {
 		return reconcile.Result{}, errors.Wrap(err, "update status")
 	}
-	return reconcile.Result{}, nil
+	requeue := plan.requeueAfter
+	if heldWait > 0 && (requeue == 0 || heldWait < requeue) {
+		requeue = heldWait
+	}
+	return reconcile.Result{RequeueAfter: requeue}, nil
+}
+
+// getTenant retrieves the tenant of the AppRollout's namespace. Rollouts in
+// the default namespace don't need to belong to a tenant.
+func getTenant(ctx context.Context, kube kclient.Client, ar *apps.AppRollout, tenant *config.Tenant) error {
+	tenantName := strings.TrimPrefix(ar.Namespace, coretools.TenantPrefix)
+	err := kube.Get(ctx, kclient.ObjectKey{Name: tenantName}, tenant)
+	if k8serrors.IsNotFound(err) && ar.Namespace == metav1.NamespaceDefault {
+		log.Printf("AppRollout %s is in default namespace. No tenant information added", ar.Name)
+	} else if k8serrors.IsNotFound(err) {
+		return errors.Errorf("Tenant %s not found", tenantName)
+	} else if err != nil {
+		return errors.Wrap(err, "get Tenant")
+	}
+	return nil
 }
 
 func (r *Reconciler) updateErrorStatus(ctx context.Context, ar *apps.AppRollout, msg string) error {
@@ -378,6 +533,7 @@ func setStatus(ar *apps.AppRollout, numWantCAs int, curCAs []apps.ChartAssignmen
 		setCondition(ar, apps.AppRolloutConditionReady, core.ConditionFalse,
 			fmt.Sprintf("%d/%d ChartAssignments ready", got, want))
 	}
+	setRobotStatus(ar, curCAs)
 }
 
 // setCondition adds or updates a condition. Existing conditions are detected based on the Type field.
@@ -429,87 +585,91 @@ func chartAssignmentChanged(prev, cur *apps.ChartAssignment) (bool, error) {
 	return !bytes.Equal(prevSpec, curSpec), nil
 }
 
-type errRobotSelectorOverlap string
-
-func (r errRobotSelectorOverlap) Error() string {
-	return fmt.Sprintf("robot %q was selected multiple times", string(r))
-}
-
 // generateChartAssignments returns a list of all cloud and robot ChartAssignments
-// for the given app, its rollout, and set of robots.
+// for the given app, its rollout, and set of robots, along with the entries of
+// spec.robots that were applied to the robots.
 func generateChartAssignments(
 	app *apps.App,
 	rollout *apps.AppRollout,
 	tenant *config.Tenant,
 	allRobots []registry.Robot,
 	baseValues chartutil.Values,
-) ([]*apps.ChartAssignment, error) {
+) ([]*apps.ChartAssignment, []robotSelection, error) {
 	var (
 		cas   []*apps.ChartAssignment
 		comps = app.Spec.Components
-		// Robots that matched selectors for the rollout and which will be
-		// passed to the cloud chart.
-		selectedRobots = map[string]*registry.Robot{}
 	)
-	for _, rcomp := range rollout.Spec.Robots {
-		robots, err := matchingRobots(allRobots, rcomp.Selector)
+	// Robots that matched selectors for the rollout and which will be
+	// passed to the cloud chart.
+	selections, err := selectRobots(rollout.Spec.Robots, allRobots)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "select robots")
+	}
+	if comps.Robot.Name != "" || comps.Robot.Inline != "" {
+		templates, err := parseValuesTemplates(rollout.Spec.Robots)
 		if err != nil {
-			return nil, errors.Wrap(err, "select robots")
-		}
-		for i := range robots {
-			// Ensure we don't pass a pointer to the most recent loop item.
-			r := &robots[i]
-			// No robot must be selected multiple times.
-			if _, ok := selectedRobots[r.Name]; ok {
-				return nil, errRobotSelectorOverlap(r.Name)
-			}
-			selectedRobots[r.Name] = r
-
-			if comps.Robot.Name != "" || comps.Robot.Inline != "" {
-				cas = append(cas, newRobotChartAssignment(r, app, rollout, tenant, &rcomp, baseValues))
+			return nil, nil, err
+		}
+		for _, s := range selections {
+			ca, err := newRobotChartAssignment(s.robot, app, rollout, tenant, &rollout.Spec.Robots[s.entry], templates[s.entry], baseValues)
+			if err != nil {
+				return nil, nil, err
 			}
+			cas = append(cas, ca)
 		}
 	}
 	if comps.Cloud.Name != "" || comps.Cloud.Inline != "" {
-		// Turn robot map into a sorted slice so we produce deterministic outputs.
-		// (Go randomizes map iteration.)
-		robots := make([]*registry.Robot, 0, len(selectedRobots))
-		for _, r := range selectedRobots {
-			robots = append(robots, r)
-		}
-		sort.Slice(robots, func(i, j int) bool {
-			return robots[i].Name < robots[j].Name
-		})
-		cas = append(cas, newCloudChartAssignment(app, rollout, tenant, baseValues, robots...))
+		// Selections are sorted by robot name so we produce deterministic outputs.
+		robots := make([]*registry.Robot, 0, len(selections))
+		for _, s := range selections {
+			robots = append(robots, s.robot)
+		}
+		clusters := cloudClusters(&rollout.Spec.Cloud)
+		if comps.Robot.Name != "" || comps.Robot.Inline != "" {
+			if err := checkClusterOverlap(clusters, selections); err != nil {
+				return nil, nil, err
+			}
+		}
+		for _, cluster := range clusters {
+			cas = append(cas, newCloudChartAssignment(app, rollout, tenant, cluster, baseValues, robots...))
+		}
 	}
 	sort.Slice(cas, func(i, j int) bool {
 		return cas[i].Name < cas[j].Name
 	})
-	return cas, nil
+	return cas, selections, nil
 }
 
-// newCloudChartAssignment generates a new ChartAssignment for the cloud cluster
+// newCloudChartAssignment generates a new ChartAssignment for a cloud cluster
 // from an app, it's rollout, a set of base configuration values,
 // and a list of robots matched by the rollout.
 func newCloudChartAssignment(
 	app *apps.App,
 	rollout *apps.AppRollout,
 	tenant *config.Tenant,
+	cluster string,
 	values chartutil.Values,
 	robots ...*registry.Robot,
 ) *apps.ChartAssignment {
 	ca := newBaseChartAssignment(app, rollout, &app.Spec.Components.Cloud)
 
-	ca.Name = chartAssignmentName(rollout.Name, compTypeCloud, "")
+	// The ChartAssignment for the central cloud cluster keeps its name
+	// without a cluster suffix.
+	suffix := cluster
+	if cluster == cloudClusterName {
+		suffix = ""
+	}
+	ca.Name = chartAssignmentName(rollout.Name, compTypeCloud, suffix)
 	ca.Namespace = rollout.Namespace
-	ca.Spec.ClusterName = "cloud"
+	ca.Spec.ClusterName = cluster
+	if cluster != cloudClusterName {
+		setLabel(&ca.ObjectMeta, labelClusterName, cluster)
+	}
 
 	// Generate robot values list that's injected into the cloud chart.
-	var robotValuesList []robotValues
+	var robotValuesList []interface{}
 	for _, r := range robots {
-		robotValuesList = append(robotValuesList, robotValues{
-			Name: r.Name,
-		})
+		robotValuesList = append(robotValuesList, robotValues(r))
 	}
 	vals := chartutil.Values{}
 	vals.MergeInto(values)
@@ -529,15 +689,17 @@ func newCloudChartAssignment(
 }
 
 // newRobotChartAssignment generates a new ChartAssignment for a robot cluster
-// from an app, its rollout, and a set of base configuration values.
+// from an app, its rollout, and a set of base configuration values. tmpl is
+// the parsed values template of spec, or nil.
 func newRobotChartAssignment(
 	robot *registry.Robot,
 	app *apps.App,
 	rollout *apps.AppRollout,
 	tenant *config.Tenant,
 	spec *apps.AppRolloutSpecRobot,
+	tmpl *template.Template,
 	values chartutil.Values,
-) *apps.ChartAssignment {
+) (*apps.ChartAssignment, error) {
 	ca := newBaseChartAssignment(app, rollout, &app.Spec.Components.Robot)
 
 	ca.Name = chartAssignmentName(rollout.Name, compTypeRobot, robot.Name)
@@ -552,7 +714,14 @@ func newRobotChartAssignment(
 	vals := chartutil.Values{}
 	vals.MergeInto(values)
 	vals.MergeInto(chartutil.Values(spec.Values))
-	vals.MergeInto(chartutil.Values{"robot": robotValues{Name: robot.Name}})
+	if tmpl != nil {
+		tvals, err := renderValuesTemplate(tmpl, robot)
+		if err != nil {
+			return nil, errValuesTemplate{robot: robot.Name, err: err}
+		}
+		vals.MergeInto(tvals)
+	}
+	vals.MergeInto(chartutil.Values{"robot": robotValues(robot)})
 	if tenant.Name != "" {
 		vals.MergeInto(chartutil.Values{"tenant": tenant.Name})
 		vals.MergeInto(chartutil.Values{"tenant_domain": tenant.Status.TenantDomain})
@@ -562,7 +731,7 @@ func newRobotChartAssignment(
 
 	ca.Spec.Chart.Values = apps.ConfigValues(vals)
 
-	return ca
+	return ca, nil
 }
 
 // newChartAssignments returns a new ChartAssignments that's initialized with
@@ -576,11 +745,13 @@ func newBaseChartAssignment(app *apps.App, rollout *apps.AppRollout, comp *apps.
 		setLabel(&ca.ObjectMeta, k, v)
 	}
 	for k, v := range rollout.Annotations {
-		if k != core.LastAppliedConfigAnnotation {
+		if k != core.LastAppliedConfigAnnotation && k != annotationDryRun {
 			setAnnotation(&ca.ObjectMeta, k, v)
 		}
 	}
+	setLabel(&ca.ObjectMeta, chartassignment.LabelAppName, app.Name)
 	ca.Spec.NamespaceName = appNamespaceName(rollout.Namespace, rollout.Name)
+	ca.Spec.NetworkIsolation = networkIsolation(app)
 
 	if comp.Name != "" {
 		ca.Spec.Chart = apps.AssignedChart{
@@ -630,17 +801,18 @@ const (
 	compTypeCloud componentType = "cloud"
 )
 
-func chartAssignmentName(rollout string, typ componentType, robot string) string {
-	if robot != "" {
-		return fmt.Sprintf("%s-%s-%s", rollout, typ, robot)
+func chartAssignmentName(rollout string, typ componentType, cluster string) string {
+	if cluster != "" {
+		return fmt.Sprintf("%s-%s-%s", rollout, typ, cluster)
 	}
 	return fmt.Sprintf("%s-%s", rollout, typ)
 }
 
-// robotValues is the struct that is passed into the cloud chart configuration
-// for each robot matched by a rollout.
-type robotValues struct {
-	Name string `json:"name"`
+// robotValues returns the values that are passed into the chart configuration
+// for each robot matched by a rollout. They only consist of JSON types so that
+// ChartAssignments can be deep-copied.
+func robotValues(r *registry.Robot) map[string]interface{} {
+	return map[string]interface{}{"name": r.Name}
 }
 
 func setLabel(o *metav1.ObjectMeta, k, v string) {
@@ -684,13 +856,20 @@ func indexAppName(o kclient.Object) []string {
 }
 
 // NewValidationWebhook returns a new webhook that validates AppRollouts.
-func NewValidationWebhook(mgr manager.Manager) *admission.Webhook {
-	return &admission.Webhook{Handler: newAppRolloutValidator(mgr.GetScheme())}
+// The values that would be assigned to the app's charts are validated
+// against the charts' values schemas.
+func NewValidationWebhook(mgr manager.Manager, baseValues chartutil.Values) *admission.Webhook {
+	v := newAppRolloutValidator(mgr.GetScheme())
+	v.kube = mgr.GetClient()
+	v.baseValues = baseValues
+	return &admission.Webhook{Handler: v}
 }
 
 // appRolloutValidator implements a validation webhook.
 type appRolloutValidator struct {
-	decoder runtime.Decoder
+	decoder    runtime.Decoder
+	kube       kclient.Client
+	baseValues chartutil.Values
 }
 
 func newAppRolloutValidator(sc *runtime.Scheme) *appRolloutValidator {
@@ -699,7 +878,7 @@ func newAppRolloutValidator(sc *runtime.Scheme) *appRolloutValidator {
 	}
 }
 
-func (v *appRolloutValidator) Handle(_ context.Context, req admission.Request) admission.Response {
+func (v *appRolloutValidator) Handle(ctx context.Context, req admission.Request) admission.Response {
 	cur := &apps.AppRollout{}
 
 	if err := runtime.DecodeInto(v.decoder, req.AdmissionRequest.Object.Raw, cur); err != nil {
@@ -708,9 +887,69 @@ func (v *appRolloutValidator) Handle(_ context.Context, req admission.Request) a
 	if err := validate(cur); err != nil {
 		return admission.Denied(err.Error())
 	}
+	if v.kube != nil {
+		if err := v.validateValues(ctx, cur); err != nil {
+			if _, ok := errors.Cause(err).(*chartassignment.ValuesSchemaError); ok {
+				return admission.Denied(err.Error())
+			}
+			// Don't block admission if the app or its charts cannot be
+			// retrieved right now. The ChartAssignment controller validates
+			// values again before rendering.
+			return admission.Allowed("").WithWarnings(fmt.Sprintf("values not validated: %s", err))
+		}
+	}
 	return admission.Allowed("")
 }
 
+// validateValues validates the values that the rollout assigns to the app's
+// charts against the charts' values schemas. Robot charts are validated for
+// a placeholder robot as the robot-specific values only differ in the name.
+func (v *appRolloutValidator) validateValues(ctx context.Context, ar *apps.AppRollout) error {
+	var (
+		app    apps.App
+		tenant config.Tenant
+	)
+	if err := v.kube.Get(ctx, kclient.ObjectKey{Name: ar.Spec.AppName}, &app); err != nil {
+		return errors.Wrapf(err, "get App %q", ar.Spec.AppName)
+	}
+	if err := getTenant(ctx, v.kube, ar, &tenant); err != nil {
+		return err
+	}
+	release, err := getRelease(ctx, v.kube, ar)
+	if err != nil {
+		return err
+	}
+	resolvedApp, ar, err := resolveChannels(&app, ar, release)
+	if err != nil {
+		return err
+	}
+	app = *resolvedApp
+	var (
+		comps  = app.Spec.Components
+		charts []*apps.AssignedChart
+	)
+	if comps.Cloud.Name != "" || comps.Cloud.Inline != "" {
+		ca := newCloudChartAssignment(&app, ar, &tenant, cloudClusterName, v.baseValues)
+		charts = append(charts, &ca.Spec.Chart)
+	}
+	if comps.Robot.Name != "" || comps.Robot.Inline != "" {
+		var robot registry.Robot
+		robot.Name = "robot"
+		templates, err := parseValuesTemplates(ar.Spec.Robots)
+		if err != nil {
+			return err
+		}
+		for i := range ar.Spec.Robots {
+			ca, err := newRobotChartAssignment(&robot, &app, ar, &tenant, &ar.Spec.Robots[i], templates[i], v.baseValues)
+			if err != nil {
+				return err
+			}
+			charts = append(charts, &ca.Spec.Chart)
+		}
+	}
+	return chartassignment.ValidateValues(charts...)
+}
+
 func validate(cur *apps.AppRollout) error {
 	if cur.Spec.AppName == "" {
 		return errors.New("app name missing")
@@ -722,6 +961,21 @@ func validate(cur *apps.AppRollout) error {
 	if _, ok := cur.Spec.Cloud.Values["robots"]; ok {
 		return errors.Errorf(".spec.cloud.values.robots is a reserved field and must not be set")
 	}
+	if err := validateStrategy(cur.Spec.Strategy); err != nil {
+		return errors.Wrap(err, "validate strategy")
+	}
+	if err := validateRollback(&cur.Spec); err != nil {
+		return errors.Wrap(err, "validate rollback")
+	}
+	if err := validateGates(cur.Spec.Gates); err != nil {
+		return errors.Wrap(err, "validate gates")
+	}
+	if err := validateChannels(&cur.Spec); err != nil {
+		return errors.Wrap(err, "validate channels")
+	}
+	if err := validateCloudClusters(&cur.Spec.Cloud); err != nil {
+		return errors.Wrap(err, "validate cloud clusters")
+	}
 	for i, r := range cur.Spec.Robots {
 		if _, ok := r.Values["robot"]; ok {
 			return errors.Errorf(".spec.robots[].values.robot is a reserved field and must not be set")
@@ -729,6 +983,9 @@ func validate(cur *apps.AppRollout) error {
 		if r.Selector == nil {
 			return errors.Errorf("no selector provided for robots %d", i)
 		}
+		if _, err := parseValuesTemplate(r.ValuesTemplate); err != nil {
+			return errors.Wrapf(err, "invalid values template for robots %d", i)
+		}
 		// Reject if a selector has neither a matcher nor `any` set.
 		// This mostly helps catching missing `matchLabels`.
 		if r.Selector.Any == nil && r.Selector.LabelSelector == nil {
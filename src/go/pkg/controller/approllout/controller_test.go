@@ -15,14 +15,24 @@
 package approllout
 
 import (
+	"context"
+	"fmt"
+	"reflect"
 	"strings"
 	"testing"
+	"text/template"
 
 	apps "github.com/SAP/cloud-robotics/src/go/pkg/apis/apps/v1alpha1"
 	config "github.com/SAP/cloud-robotics/src/go/pkg/apis/config/v1alpha1"
 	registry "github.com/SAP/cloud-robotics/src/go/pkg/apis/registry/v1alpha1"
+	"github.com/SAP/cloud-robotics/src/go/pkg/controller/chartassignment"
+	"github.com/SAP/cloud-robotics/src/go/pkg/kubetest"
+	"github.com/pkg/errors"
 	core "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/helm/pkg/chartutil"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/yaml"
 )
 
@@ -104,6 +114,7 @@ metadata:
   namespace: default
   labels:
     lkey1: lval1
+    cloudrobotics.com/app-name: foo
     cloudrobotics.com/robot-name: robot1
   annotations:
     akey1: aval1
@@ -124,8 +135,119 @@ spec:
 
 	var tenant config.Tenant
 
-	result := newRobotChartAssignment(&robot, &app, &rollout, &tenant, &rollout.Spec.Robots[0], baseValues)
+	result, err := newRobotChartAssignment(&robot, &app, &rollout, &tenant, &rollout.Spec.Robots[0], nil, baseValues)
+	if err != nil {
+		t.Fatal(err)
+	}
+	verifyChartAssignment(t, &expected, result)
+}
+
+func TestNewRobotChartAssignment_valuesTemplate(t *testing.T) {
+	var app apps.App
+	unmarshalYAML(t, &app, `
+metadata:
+  name: foo
+spec:
+  components:
+    robot:
+      inline: abcdefgh
+	`)
+
+	var rollout apps.AppRollout
+	unmarshalYAML(t, &rollout, `
+metadata:
+  name: foo-rollout
+  namespace: default
+spec:
+  appName: foo
+  robots:
+  - selector:
+      any: true
+    values:
+      map: default
+      lidar:
+        frame: laser
+    valuesTemplate: |
+      map: {{ index .Robot.Labels "site" }}-map
+      fleet: {{ .Robot.Annotations.fleet | quote }}
+      {{- if eq .Robot.Type "mir-100" }}
+      lidar:
+        frame: base_laser
+      {{- end }}
+ `)
+
+	var robot registry.Robot
+	unmarshalYAML(t, &robot, `
+metadata:
+  name: robot1
+  labels:
+    site: wdf
+  annotations:
+    fleet: "42"
+spec:
+  type: mir-100
+	`)
+
+	var expected apps.ChartAssignment
+	unmarshalYAML(t, &expected, `
+metadata:
+  name: foo-rollout-robot-robot1
+  namespace: default
+  labels:
+    cloudrobotics.com/app-name: foo
+    cloudrobotics.com/robot-name: robot1
+spec:
+  clusterName: robot1
+  namespaceName: app-foo-rollout
+  chart:
+    inline: abcdefgh
+    values:
+      robot:
+        name: robot1
+      map: wdf-map
+      fleet: "42"
+      lidar:
+        frame: base_laser
+	`)
+
+	var tenant config.Tenant
+
+	tmpl := mustParseValuesTemplate(t, rollout.Spec.Robots[0].ValuesTemplate)
+	result, err := newRobotChartAssignment(&robot, &app, &rollout, &tenant, &rollout.Spec.Robots[0], tmpl, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
 	verifyChartAssignment(t, &expected, result)
+
+	// Rendering errors are reported for the robot.
+	tmpl = mustParseValuesTemplate(t, `{{ .Robot.Labels.site.nope }}`)
+	_, err = newRobotChartAssignment(&robot, &app, &rollout, &tenant, &rollout.Spec.Robots[0], tmpl, nil)
+	if _, ok := err.(errValuesTemplate); !ok {
+		t.Fatalf("expected values template error, got %v", err)
+	}
+}
+
+func mustParseValuesTemplate(t *testing.T, text string) *template.Template {
+	t.Helper()
+	tmpl, err := parseValuesTemplate(text)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tmpl
+}
+
+func TestParseValuesTemplate_rejectsNonRepeatableFuncs(t *testing.T) {
+	for _, text := range []string{
+		`home: {{ env "HOME" }}`,
+		`path: {{ expandenv "$PATH" }}`,
+		`time: {{ now }}`,
+		`id: {{ uuidv4 }}`,
+		`password: {{ randAlphaNum 16 }}`,
+	} {
+		if _, err := parseValuesTemplate(text); err == nil {
+			t.Errorf("expected error for template %q, got none", text)
+		}
+	}
 }
 
 func TestNewCloudChartAssignment(t *testing.T) {
@@ -180,6 +302,7 @@ metadata:
   namespace: default
   labels:
     lkey1: lval1
+    cloudrobotics.com/app-name: foo
   annotations:
     akey1: aval1
 spec:
@@ -200,7 +323,7 @@ spec:
 
 	var tenant config.Tenant
 
-	result := newCloudChartAssignment(&app, &rollout, &tenant, baseValues, &robot1, &robot2)
+	result := newCloudChartAssignment(&app, &rollout, &tenant, "cloud", baseValues, &robot1, &robot2)
 	verifyChartAssignment(t, &expected, result)
 }
 
@@ -271,6 +394,8 @@ spec:
 metadata:
   name: foo-rollout-cloud
   namespace: default
+  labels:
+    cloudrobotics.com/app-name: foo
 spec:
   clusterName: cloud
   namespaceName: app-foo-rollout
@@ -288,6 +413,7 @@ metadata:
   name: foo-rollout-robot-robot1
   namespace: default
   labels:
+    cloudrobotics.com/app-name: foo
     cloudrobotics.com/robot-name: robot1
 spec:
   clusterName: robot1
@@ -304,6 +430,7 @@ metadata:
   name: foo-rollout-robot-robot3
   namespace: default
   labels:
+    cloudrobotics.com/app-name: foo
     cloudrobotics.com/robot-name: robot3
 spec:
   clusterName: robot3
@@ -319,7 +446,7 @@ spec:
 
 	var tenant config.Tenant
 
-	cas, err := generateChartAssignments(&app, &rollout, &tenant, robots[:], baseValues)
+	cas, _, err := generateChartAssignments(&app, &rollout, &tenant, robots[:], baseValues)
 	if err != nil {
 		t.Fatalf("Generate failed: %s", err)
 	}
@@ -378,6 +505,8 @@ spec:
 metadata:
   name: foo-rollout-cloud
   namespace: default
+  labels:
+    cloudrobotics.com/app-name: foo
 spec:
   clusterName: cloud
   namespaceName: app-foo-rollout
@@ -390,7 +519,7 @@ spec:
 
 	var tenant config.Tenant
 
-	cas, err := generateChartAssignments(&app, &rollout, &tenant, robots[:], nil)
+	cas, _, err := generateChartAssignments(&app, &rollout, &tenant, robots[:], nil)
 	if err != nil {
 		t.Fatalf("Generate failed: %s", err)
 	}
@@ -411,7 +540,7 @@ spec:
       inline: inline-robot
 	`)
 
-	var robots [2]registry.Robot
+	var robots [3]registry.Robot
 	unmarshalYAML(t, &robots[0], `
 metadata:
   name: robot1
@@ -422,8 +551,17 @@ metadata:
   labels:
     a: b
 	`)
+	unmarshalYAML(t, &robots[2], `
+metadata:
+  name: robot3
+  labels:
+    a: b
+    c: d
+	`)
 
-	// Rollout with two selectors that match the same robot.
+	// Rollout with a default selector and overrides that match the same
+	// robots. The more specific selector wins for robot2, while the
+	// priority decides for robot3.
 	var rollout apps.AppRollout
 	unmarshalYAML(t, &rollout, `
 metadata:
@@ -434,16 +572,46 @@ spec:
   robots:
   - selector:
       any: true
+    version: default
   - selector:
       matchLabels:
         a: b
+    version: specific
+  - selector:
+      matchLabels:
+        c: d
+    version: priority
+    priority: 1
 	`)
 
 	var tenant config.Tenant
 
-	_, err := generateChartAssignments(&app, &rollout, &tenant, robots[:], nil)
-	if exp := errRobotSelectorOverlap("robot2"); err != exp {
-		t.Fatalf("expected error %q but got %q", exp, err)
+	cas, selections, err := generateChartAssignments(&app, &rollout, &tenant, robots[:], nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := map[string]string{}
+	for _, ca := range cas {
+		got[ca.Name] = ca.Spec.Chart.Version
+	}
+	want := map[string]string{
+		"foo-rollout-robot-robot1": "default",
+		"foo-rollout-robot-robot2": "specific",
+		"foo-rollout-robot-robot3": "priority",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected chart versions: got %v, want %v", got, want)
+	}
+
+	setRobotSelections(&rollout, selections)
+
+	wantSelections := []apps.AppRolloutRobotSelection{
+		{Name: "robot1", Entry: 0, Matched: []int32{0}},
+		{Name: "robot2", Entry: 1, Matched: []int32{0, 1}},
+		{Name: "robot3", Entry: 2, Matched: []int32{0, 1, 2}},
+	}
+	if !reflect.DeepEqual(rollout.Status.RobotSelections, wantSelections) {
+		t.Errorf("unexpected robot selections: got %+v, want %+v", rollout.Status.RobotSelections, wantSelections)
 	}
 }
 
@@ -532,6 +700,80 @@ spec:
   appName: my-app.123
 	`,
 		},
+		{
+			name: "valid-gates",
+			cur: `
+spec:
+  appName: myapp
+  gates:
+    maintenanceWindows:
+    - schedule: "0 2 * * 1-5"
+      duration: 2h
+      timeZone: Europe/Berlin
+    robotStates: [AVAILABLE]
+	`,
+		},
+		{
+			name: "invalid-maintenance-window",
+			cur: `
+spec:
+  appName: myapp
+  gates:
+    maintenanceWindows:
+    - schedule: "0 25 * * *"
+      duration: 2h
+	`,
+			shouldFail: true,
+		},
+		{
+			name: "invalid-values-template",
+			cur: `
+spec:
+  appName: myapp
+  robots:
+  - selector:
+      any: true
+    valuesTemplate: "{{ .Robot.Name"
+	`,
+			shouldFail: true,
+		},
+		{
+			name: "valid-channels",
+			cur: `
+spec:
+  appName: myapp
+  releaseName: myapp
+  channel: production
+  robots:
+  - selector:
+      matchLabels:
+        stage: staging
+    channel: staging
+	`,
+		},
+		{
+			name: "channel-without-release",
+			cur: `
+spec:
+  appName: myapp
+  channel: production
+	`,
+			shouldFail: true,
+		},
+		{
+			name: "channel-and-version",
+			cur: `
+spec:
+  appName: myapp
+  releaseName: myapp
+  robots:
+  - selector:
+      any: true
+    channel: staging
+    version: 1.0.0
+	`,
+			shouldFail: true,
+		},
 		{
 			name:       "missing-app-name",
 			cur:        `spec: {}`,
@@ -576,6 +818,38 @@ spec:
     values:
       robots:
         c: d
+	`,
+			shouldFail: true,
+		},
+		{
+			name: "valid-cloud-clusters",
+			cur: `
+spec:
+  appName: myapp
+  cloud:
+    clusters: [cloud, edge-1]
+    clusterSelector:
+      matchLabels:
+        cloudrobotics.com/cluster-type: edge
+	`,
+		},
+		{
+			name: "invalid-cloud-cluster",
+			cur: `
+spec:
+  appName: myapp
+  cloud:
+    clusters: [Edge_1]
+	`,
+			shouldFail: true,
+		},
+		{
+			name: "duplicate-cloud-cluster",
+			cur: `
+spec:
+  appName: myapp
+  cloud:
+    clusters: [edge-1, edge-1]
 	`,
 			shouldFail: true,
 		},
@@ -609,3 +883,107 @@ spec:
 		})
 	}
 }
+
+func TestValidateValues(t *testing.T) {
+	var app apps.App
+	unmarshalYAML(t, &app, `
+metadata:
+  name: foo
+	`)
+	app.Spec.Components.Robot.Inline = kubetest.BuildInlineChartWithFiles(t, "foo-robot", map[string]string{
+		"values.schema.json": `{
+  "type": "object",
+  "required": ["mapName"],
+  "properties": {
+    "mapName": {"type": "string"}
+  }
+}`,
+	})
+
+	sc := runtime.NewScheme()
+	scheme.AddToScheme(sc)
+	apps.AddToScheme(sc)
+	config.AddToScheme(sc)
+	v := newAppRolloutValidator(sc)
+	v.kube = fake.NewClientBuilder().WithScheme(sc).WithObjects(&app).Build()
+
+	cases := []struct {
+		name       string
+		rollout    string
+		shouldFail bool
+	}{
+		{
+			name: "valid",
+			rollout: `
+metadata:
+  name: foo-rollout
+  namespace: default
+spec:
+  appName: foo
+  robots:
+  - selector:
+      any: true
+    values:
+      mapName: warehouse
+	`,
+		},
+		{
+			name: "missing-required-value",
+			rollout: `
+metadata:
+  name: foo-rollout
+  namespace: default
+spec:
+  appName: foo
+  robots:
+  - selector:
+      any: true
+    values:
+      mapName: warehouse
+  - selector:
+      matchLabels:
+        model: x
+	`,
+			shouldFail: true,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var ar apps.AppRollout
+			unmarshalYAML(t, &ar, c.rollout)
+
+			err := v.validateValues(context.Background(), &ar)
+			if err == nil && c.shouldFail {
+				t.Fatal("expected failure but got none")
+			}
+			if err != nil && !c.shouldFail {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if _, ok := errors.Cause(err).(*chartassignment.ValuesSchemaError); err != nil && !ok {
+				t.Fatalf("expected ValuesSchemaError, got: %s", err)
+			}
+		})
+	}
+}
+
+func TestSetRobotSelections_bounded(t *testing.T) {
+	robots := make([]registry.Robot, maxStatusRobots+5)
+	for i := range robots {
+		robots[i].Name = fmt.Sprintf("robot%02d", i)
+	}
+	_true := true
+	entries := []apps.AppRolloutSpecRobot{{Selector: &apps.RobotSelector{Any: &_true}}}
+	selections, err := selectRobots(entries, robots)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ar apps.AppRollout
+	setRobotSelections(&ar, selections)
+
+	if got := len(ar.Status.RobotSelections); got != maxStatusRobots {
+		t.Fatalf("expected %d robot selections, got %d", maxStatusRobots, got)
+	}
+	if got := ar.Status.OmittedRobotSelections; got != 5 {
+		t.Errorf("expected 5 omitted robot selections, got %d", got)
+	}
+}
@@ -0,0 +1,335 @@
+// Copyright 2026 The Cloud Robotics Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package approllout
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/Masterminds/semver"
+	apps "github.com/SAP/cloud-robotics/src/go/pkg/apis/apps/v1alpha1"
+	"github.com/SAP/cloud-robotics/src/go/pkg/controller/chartassignment"
+	"github.com/pkg/errors"
+	core "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/workqueue"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const fieldIndexAppDependencies = "spec.dependencies.name"
+
+type errInvalidDependency struct {
+	name string
+	err  error
+}
+
+func (e errInvalidDependency) Error() string {
+	return fmt.Sprintf("invalid dependency %q: %s", e.name, e.err)
+}
+
+// errDependencyCycle is returned if an app depends on itself through other
+// apps. path lists the apps of the cycle, starting and ending with the app.
+type errDependencyCycle struct {
+	path []string
+}
+
+func (e errDependencyCycle) Error() string {
+	return fmt.Sprintf("dependency cycle %s", strings.Join(e.path, " -> "))
+}
+
+// dependencyState holds the deployed ChartAssignments of an app's
+// dependencies in the rollout's namespace.
+type dependencyState struct {
+	// cas maps dependency names to their ChartAssignments by cluster. There
+	// are multiple ChartAssignments on a cluster if the dependency has
+	// multiple rollouts. They are sorted by name.
+	cas map[string]map[string][]*apps.ChartAssignment
+	// versions maps dependency names to their App version, which is used
+	// if the ChartAssignment doesn't name a chart version.
+	versions map[string]string
+}
+
+// getDependencyState lists the ChartAssignments of the app's dependencies
+// in the rollout's namespace.
+func (r *Reconciler) getDependencyState(ctx context.Context, ar *apps.AppRollout, app *apps.App) (*dependencyState, error) {
+	st := &dependencyState{
+		cas:      map[string]map[string][]*apps.ChartAssignment{},
+		versions: map[string]string{},
+	}
+	for _, dep := range app.Spec.Dependencies {
+		var depApp apps.App
+		if err := r.kube.Get(ctx, kclient.ObjectKey{Name: dep.Name}, &depApp); err == nil {
+			st.versions[dep.Name] = depApp.Spec.Version
+		} else if !k8serrors.IsNotFound(err) {
+			return nil, errors.Wrapf(err, "get App %q", dep.Name)
+		}
+		var cas apps.ChartAssignmentList
+		err := r.kube.List(ctx, &cas,
+			kclient.InNamespace(ar.Namespace),
+			kclient.MatchingLabels{chartassignment.LabelAppName: dep.Name},
+		)
+		if err != nil {
+			return nil, errors.Wrapf(err, "list ChartAssignments for App %q", dep.Name)
+		}
+		sort.Slice(cas.Items, func(i, j int) bool {
+			return cas.Items[i].Name < cas.Items[j].Name
+		})
+		byCluster := map[string][]*apps.ChartAssignment{}
+		for i := range cas.Items {
+			cluster := cas.Items[i].Spec.ClusterName
+			byCluster[cluster] = append(byCluster[cluster], &cas.Items[i])
+		}
+		st.cas[dep.Name] = byCluster
+	}
+	return st, nil
+}
+
+// validateDependencies checks that the app doesn't depend on itself and
+// that all version constraints are well-formed.
+func validateDependencies(app *apps.App) error {
+	for _, dep := range app.Spec.Dependencies {
+		if dep.Name == app.Name {
+			return errInvalidDependency{dep.Name, errors.New("app must not depend on itself")}
+		}
+		if dep.Version == "" {
+			continue
+		}
+		if _, err := semver.NewConstraint(dep.Version); err != nil {
+			return errInvalidDependency{dep.Name, err}
+		}
+	}
+	return nil
+}
+
+// checkDependencyCycle returns an errDependencyCycle if the app depends on
+// itself through other apps. It follows the apps that depend on the app
+// through the dependency index.
+func (r *Reconciler) checkDependencyCycle(ctx context.Context, app *apps.App) error {
+	visited := map[string]bool{}
+	// path holds the apps from the app to the current one, each depending
+	// on the previous one.
+	var visit func(path []string) error
+	visit = func(path []string) error {
+		name := path[len(path)-1]
+		var dependents apps.AppList
+		err := r.kube.List(ctx, &dependents, kclient.MatchingFields(map[string]string{fieldIndexAppDependencies: name}))
+		if err != nil {
+			return errors.Wrapf(err, "list Apps depending on %s", name)
+		}
+		sort.Slice(dependents.Items, func(i, j int) bool {
+			return dependents.Items[i].Name < dependents.Items[j].Name
+		})
+		for i := range dependents.Items {
+			d := &dependents.Items[i]
+			if d.Name == app.Name {
+				// Use the app's own spec, which may be newer than
+				// the cache.
+				d = app
+			}
+			if !dependsOn(d, name) {
+				continue
+			}
+			if d.Name == app.Name {
+				cycle := []string{app.Name}
+				for i := len(path) - 1; i >= 0; i-- {
+					cycle = append(cycle, path[i])
+				}
+				return errDependencyCycle{path: cycle}
+			}
+			if visited[d.Name] {
+				continue
+			}
+			visited[d.Name] = true
+			if err := visit(append(path[:len(path):len(path)], d.Name)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return visit([]string{app.Name})
+}
+
+func dependsOn(app *apps.App, name string) bool {
+	for _, dep := range app.Spec.Dependencies {
+		if dep.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// networkIsolation returns the network isolation of the app's
+// ChartAssignments. Pods may connect to the namespaces of the app's
+// dependencies.
+func networkIsolation(app *apps.App) *apps.NetworkIsolation {
+	ni := app.Spec.NetworkIsolation.DeepCopy()
+	if ni == nil {
+		return nil
+	}
+	ni.Dependencies = nil
+	for _, dep := range app.Spec.Dependencies {
+		ni.Dependencies = append(ni.Dependencies, dep.Name)
+	}
+	return ni
+}
+
+// dependencyHoldReason returns why a ChartAssignment on the cluster has to
+// wait for the dependency, or an empty string if the dependency is ready. If
+// the dependency has multiple ChartAssignments on the cluster, it's ready if
+// any of them is, and the reason of the first one is returned otherwise.
+func dependencyHoldReason(dep apps.AppDependency, st *dependencyState, cluster string) (string, error) {
+	cas := st.cas[dep.Name][cluster]
+	if len(cas) == 0 {
+		return fmt.Sprintf("dependency %s is not deployed", dep.Name), nil
+	}
+	var first string
+	for _, ca := range cas {
+		reason, err := chartAssignmentHoldReason(dep, st, ca)
+		if err != nil || reason == "" {
+			return "", err
+		}
+		if first == "" {
+			first = reason
+		}
+	}
+	return first, nil
+}
+
+// chartAssignmentHoldReason returns why the ChartAssignment of the
+// dependency isn't ready to be depended on, or an empty string if it is.
+func chartAssignmentHoldReason(dep apps.AppDependency, st *dependencyState, ca *apps.ChartAssignment) (string, error) {
+	if !chartAssignmentReady(ca) {
+		return fmt.Sprintf("dependency %s is not ready", dep.Name), nil
+	}
+	if dep.Version == "" {
+		return "", nil
+	}
+	c, err := semver.NewConstraint(dep.Version)
+	if err != nil {
+		return "", errInvalidDependency{dep.Name, err}
+	}
+	version := ca.Spec.Chart.Version
+	if version == "" {
+		version = st.versions[dep.Name]
+	}
+	v, err := semver.NewVersion(version)
+	if err != nil {
+		return fmt.Sprintf("dependency %s has no semantic version", dep.Name), nil
+	}
+	if !c.Check(v) {
+		return fmt.Sprintf("dependency %s version %s does not satisfy %q", dep.Name, version, dep.Version), nil
+	}
+	return "", nil
+}
+
+// dependencyHeldChartAssignments returns the wanted ChartAssignments with
+// pending changes whose dependencies aren't ready on the same cluster along
+// with the reason.
+func dependencyHeldChartAssignments(
+	app *apps.App,
+	st *dependencyState,
+	wantCAs []*apps.ChartAssignment,
+	curCAs map[string]apps.ChartAssignment,
+) (map[string]string, error) {
+	held := map[string]string{}
+	if len(app.Spec.Dependencies) == 0 {
+		return held, nil
+	}
+	for _, ca := range wantCAs {
+		if prev, ok := curCAs[ca.Name]; ok {
+			if changed, err := chartAssignmentChanged(&prev, ca); err != nil {
+				return nil, errors.Wrap(err, "check ChartAssignment changed")
+			} else if !changed {
+				continue
+			}
+		}
+		for _, dep := range app.Spec.Dependencies {
+			reason, err := dependencyHoldReason(dep, st, ca.Spec.ClusterName)
+			if err != nil {
+				return nil, err
+			}
+			if reason != "" {
+				held[ca.Name] = reason
+				break
+			}
+		}
+	}
+	return held, nil
+}
+
+// setDependencyCondition reports ChartAssignments that are held back by
+// dependencies. The condition is only set for apps with dependencies or if
+// it was set before.
+func setDependencyCondition(ar *apps.AppRollout, app *apps.App, wantCAs []*apps.ChartAssignment, held map[string]string) {
+	if len(app.Spec.Dependencies) == 0 && !hasCondition(ar, apps.AppRolloutConditionDependenciesReady) {
+		return
+	}
+	var clusters []string
+	for _, ca := range wantCAs {
+		if _, ok := held[ca.Name]; ok {
+			clusters = append(clusters, ca.Spec.ClusterName)
+		}
+	}
+	if len(clusters) == 0 {
+		setCondition(ar, apps.AppRolloutConditionDependenciesReady, core.ConditionTrue, "")
+		return
+	}
+	sort.Strings(clusters)
+	msg := fmt.Sprintf("waiting for dependencies on %d clusters: %s", len(clusters), strings.Join(firstN(clusters, maxFailedRobotsInCondition), ", "))
+	if len(clusters) > maxFailedRobotsInCondition {
+		msg += fmt.Sprintf(" and %d more", len(clusters)-maxFailedRobotsInCondition)
+	}
+	setCondition(ar, apps.AppRolloutConditionDependenciesReady, core.ConditionFalse, msg)
+}
+
+func hasCondition(ar *apps.AppRollout, t apps.AppRolloutConditionType) bool {
+	for _, c := range ar.Status.Conditions {
+		if c.Type == t {
+			return true
+		}
+	}
+	return false
+}
+
+func indexAppDependencies(o kclient.Object) []string {
+	app := o.(*apps.App)
+	var names []string
+	for _, dep := range app.Spec.Dependencies {
+		names = append(names, dep.Name)
+	}
+	return names
+}
+
+// enqueueForDependents enqueues the AppRollouts of apps that depend on the
+// app of the given ChartAssignment.
+func (r *Reconciler) enqueueForDependents(ctx context.Context, m metav1.Object, q workqueue.RateLimitingInterface) {
+	name, ok := m.GetLabels()[chartassignment.LabelAppName]
+	if !ok {
+		return
+	}
+	var dependents apps.AppList
+	err := r.kube.List(ctx, &dependents, kclient.MatchingFields(map[string]string{fieldIndexAppDependencies: name}))
+	if err != nil {
+		log.Printf("List Apps depending on %s failed: %s", name, err)
+		return
+	}
+	for i := range dependents.Items {
+		r.enqueueForApp(ctx, &dependents.Items[i], q)
+	}
+}
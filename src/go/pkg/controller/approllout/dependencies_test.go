@@ -0,0 +1,254 @@
+// Copyright 2026 The Cloud Robotics Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package approllout
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	apps "github.com/SAP/cloud-robotics/src/go/pkg/apis/apps/v1alpha1"
+	"github.com/SAP/cloud-robotics/src/go/pkg/controller/chartassignment"
+	core "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestDependencyHeldChartAssignments(t *testing.T) {
+	ctx := context.Background()
+
+	var app, dep apps.App
+	unmarshalYAML(t, &app, `
+metadata:
+  name: foo
+spec:
+  dependencies:
+  - name: bar
+    version: ">=1.2.0"
+	`)
+	unmarshalYAML(t, &dep, `
+metadata:
+  name: bar
+spec:
+  version: 1.0.0
+	`)
+	// bar is ready on robot1 with a matching version override, ready on
+	// robot2 with the App's version, and still installing in the cloud.
+	var depCAs [3]apps.ChartAssignment
+	unmarshalYAML(t, &depCAs[0], `
+metadata:
+  name: bar-robot-robot1
+  namespace: default
+  labels:
+    cloudrobotics.com/app-name: bar
+spec:
+  clusterName: robot1
+  chart:
+    version: 1.3.0
+status:
+  phase: Ready
+	`)
+	unmarshalYAML(t, &depCAs[1], `
+metadata:
+  name: bar-robot-robot2
+  namespace: default
+  labels:
+    cloudrobotics.com/app-name: bar
+spec:
+  clusterName: robot2
+status:
+  phase: Ready
+	`)
+	unmarshalYAML(t, &depCAs[2], `
+metadata:
+  name: bar-cloud
+  namespace: default
+  labels:
+    cloudrobotics.com/app-name: bar
+spec:
+  clusterName: cloud
+status:
+  phase: Installing
+	`)
+	sc := runtime.NewScheme()
+	scheme.AddToScheme(sc)
+	apps.AddToScheme(sc)
+	r := &Reconciler{kube: fake.NewClientBuilder().WithScheme(sc).WithObjects(&dep, &depCAs[0], &depCAs[1], &depCAs[2]).Build()}
+
+	var ar apps.AppRollout
+	ar.Namespace = "default"
+	st, err := r.getDependencyState(ctx, &ar, &app)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var want []*apps.ChartAssignment
+	for _, cluster := range []string{"cloud", "robot1", "robot2", "robot3"} {
+		ca := &apps.ChartAssignment{}
+		ca.Name = "foo-" + cluster
+		ca.Spec.ClusterName = cluster
+		want = append(want, ca)
+	}
+	// The ChartAssignment on robot2 is already up-to-date and not held back.
+	cur := map[string]apps.ChartAssignment{"foo-robot2": *want[2].DeepCopy()}
+
+	held, err := dependencyHeldChartAssignments(&app, st, want, cur)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]string{
+		"foo-cloud":  "dependency bar is not ready",
+		"foo-robot3": "dependency bar is not deployed",
+	}
+	if !reflect.DeepEqual(held, expected) {
+		t.Errorf("unexpected held ChartAssignments: got %v, want %v", held, expected)
+	}
+
+	// The App's version is used if the ChartAssignment has none.
+	delete(cur, "foo-robot2")
+	held, err = dependencyHeldChartAssignments(&app, st, want, cur)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, exp := held["foo-robot2"], `dependency bar version 1.0.0 does not satisfy ">=1.2.0"`; got != exp {
+		t.Errorf("unexpected reason for robot2: got %q, want %q", got, exp)
+	}
+
+	setDependencyCondition(&ar, &app, want, held)
+	c := ar.Status.Conditions[0]
+	if c.Type != apps.AppRolloutConditionDependenciesReady || c.Status != core.ConditionFalse {
+		t.Fatalf("expected DependenciesReady=False, got %+v", c)
+	}
+	if exp := "waiting for dependencies on 3 clusters: cloud, robot2, robot3"; c.Message != exp {
+		t.Errorf("unexpected condition message %q, want %q", c.Message, exp)
+	}
+}
+
+func TestValidateDependencies(t *testing.T) {
+	cases := []struct {
+		name       string
+		deps       []apps.AppDependency
+		shouldFail bool
+	}{
+		{name: "none"},
+		{name: "constraint", deps: []apps.AppDependency{{Name: "bar", Version: ">=1.2.0, <2.0.0"}}},
+		{name: "self", deps: []apps.AppDependency{{Name: "foo"}}, shouldFail: true},
+		{name: "invalid-constraint", deps: []apps.AppDependency{{Name: "bar", Version: "latest"}}, shouldFail: true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			app := &apps.App{}
+			app.Name = "foo"
+			app.Spec.Dependencies = c.deps
+			err := validateDependencies(app)
+			if err == nil && c.shouldFail {
+				t.Fatal("expected failure but got none")
+			}
+			if err != nil && !c.shouldFail {
+				t.Fatalf("unexpected error: %s", err)
+			}
+		})
+	}
+}
+
+func TestDependencyHoldReason_multipleRollouts(t *testing.T) {
+	dep := apps.AppDependency{Name: "bar"}
+	var ready, installing apps.ChartAssignment
+	unmarshalYAML(t, &ready, `
+metadata:
+  name: bar-b-robot1
+  generation: 4
+spec:
+  clusterName: robot1
+status:
+  observedGeneration: 1
+  phase: Ready
+	`)
+	// The status is synced from the robot, whose generation differs.
+	ready.Status.ObservedSpecHash = chartassignment.SpecHash(&ready.Spec)
+	unmarshalYAML(t, &installing, `
+metadata:
+  name: bar-a-robot1
+spec:
+  clusterName: robot1
+status:
+  phase: Installing
+	`)
+	st := &dependencyState{cas: map[string]map[string][]*apps.ChartAssignment{
+		"bar": {"robot1": {&installing, &ready}},
+	}}
+	if reason, err := dependencyHoldReason(dep, st, "robot1"); err != nil || reason != "" {
+		t.Errorf("expected dependency to be ready, got %q, %v", reason, err)
+	}
+
+	st.cas["bar"]["robot1"] = []*apps.ChartAssignment{&installing}
+	if reason, _ := dependencyHoldReason(dep, st, "robot1"); reason != "dependency bar is not ready" {
+		t.Errorf("unexpected reason %q", reason)
+	}
+}
+
+func TestCheckDependencyCycle(t *testing.T) {
+	newApp := func(name string, deps ...string) *apps.App {
+		app := &apps.App{}
+		app.Name = name
+		for _, d := range deps {
+			app.Spec.Dependencies = append(app.Spec.Dependencies, apps.AppDependency{Name: d})
+		}
+		return app
+	}
+	sc := runtime.NewScheme()
+	apps.AddToScheme(sc)
+	r := &Reconciler{kube: fake.NewClientBuilder().WithScheme(sc).WithObjects(
+		newApp("foo", "bar"),
+		newApp("bar", "baz"),
+		newApp("baz", "foo"),
+		newApp("qux", "foo"),
+	).Build()}
+
+	err := r.checkDependencyCycle(context.Background(), newApp("foo", "bar"))
+	if _, ok := err.(errDependencyCycle); !ok {
+		t.Fatalf("expected errDependencyCycle, got %v", err)
+	}
+	if exp := "dependency cycle foo -> bar -> baz -> foo"; err.Error() != exp {
+		t.Errorf("unexpected error %q, want %q", err, exp)
+	}
+
+	// The cycle is broken by the app's new spec.
+	if err := r.checkDependencyCycle(context.Background(), newApp("foo")); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+	if err := r.checkDependencyCycle(context.Background(), newApp("qux", "foo")); err != nil {
+		t.Errorf("unexpected error for app outside of the cycle: %s", err)
+	}
+}
+
+func TestNetworkIsolation_derivedFromDependencies(t *testing.T) {
+	var app apps.App
+	unmarshalYAML(t, &app, `
+spec:
+  networkIsolation:
+    mode: Namespace
+  dependencies:
+  - name: bar
+  - name: baz
+	`)
+	ni := networkIsolation(&app)
+	if ni.Mode != apps.NetworkIsolationNamespace || !reflect.DeepEqual(ni.Dependencies, []string{"bar", "baz"}) {
+		t.Errorf("unexpected network isolation %+v", ni)
+	}
+	if app.Spec.NetworkIsolation.Dependencies != nil {
+		t.Error("expected the App not to be modified")
+	}
+}
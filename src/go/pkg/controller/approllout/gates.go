@@ -0,0 +1,311 @@
+// Copyright 2026 The Cloud Robotics Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package approllout
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	apps "github.com/SAP/cloud-robotics/src/go/pkg/apis/apps/v1alpha1"
+	registry "github.com/SAP/cloud-robotics/src/go/pkg/apis/registry/v1alpha1"
+	"github.com/pkg/errors"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// annotationDoNotDisturb on a Robot set to "true" holds back all changes
+	// to the robot's ChartAssignments.
+	annotationDoNotDisturb = "cloudrobotics.com/do-not-disturb"
+
+	// maxWindowDuration bounds maintenance windows so that searching for
+	// open windows stays cheap.
+	maxWindowDuration = 7 * 24 * time.Hour
+)
+
+// cronSchedule is a parsed cron expression with minute, hour, day of month,
+// month, and day of week fields.
+type cronSchedule struct {
+	minute, hour, dom, month, dow map[int]bool
+	// domAny and dowAny are set if the field is "*". If both day fields are
+	// restricted, a time matches if either of them matches.
+	domAny, dowAny bool
+}
+
+// parseCron parses a cron expression with five fields. Fields support "*",
+// values, ranges, lists, and steps, e.g. "0 22-23,0-4/2 * * 1-5".
+func parseCron(spec string) (*cronSchedule, error) {
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, errors.Errorf("expected 5 fields in schedule %q, got %d", spec, len(fields))
+	}
+	var (
+		s   cronSchedule
+		err error
+	)
+	if s.minute, err = parseCronField(fields[0], 0, 59); err != nil {
+		return nil, errors.Wrap(err, "minute")
+	}
+	if s.hour, err = parseCronField(fields[1], 0, 23); err != nil {
+		return nil, errors.Wrap(err, "hour")
+	}
+	if s.dom, err = parseCronField(fields[2], 1, 31); err != nil {
+		return nil, errors.Wrap(err, "day of month")
+	}
+	if s.month, err = parseCronField(fields[3], 1, 12); err != nil {
+		return nil, errors.Wrap(err, "month")
+	}
+	if s.dow, err = parseCronField(fields[4], 0, 7); err != nil {
+		return nil, errors.Wrap(err, "day of week")
+	}
+	// Both 0 and 7 are Sunday.
+	if s.dow[7] {
+		s.dow[0] = true
+	}
+	s.domAny = fields[2] == "*"
+	s.dowAny = fields[4] == "*"
+	return &s, nil
+}
+
+func parseCronField(field string, min, max int) (map[int]bool, error) {
+	res := map[int]bool{}
+	for _, part := range strings.Split(field, ",") {
+		rng, step := part, 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			var err error
+			if step, err = strconv.Atoi(part[i+1:]); err != nil || step < 1 {
+				return nil, errors.Errorf("invalid step in %q", part)
+			}
+			rng = part[:i]
+		}
+		lo, hi := min, max
+		if rng != "*" {
+			var err error
+			bounds := strings.SplitN(rng, "-", 2)
+			if lo, err = strconv.Atoi(bounds[0]); err != nil {
+				return nil, errors.Errorf("invalid value in %q", part)
+			}
+			hi = lo
+			if len(bounds) == 2 {
+				if hi, err = strconv.Atoi(bounds[1]); err != nil {
+					return nil, errors.Errorf("invalid value in %q", part)
+				}
+			}
+		}
+		if lo < min || hi > max || lo > hi {
+			return nil, errors.Errorf("%q out of range %d-%d", part, min, max)
+		}
+		for v := lo; v <= hi; v += step {
+			res[v] = true
+		}
+	}
+	return res, nil
+}
+
+// matches returns true if the schedule fires at the minute of t.
+func (s *cronSchedule) matches(t time.Time) bool {
+	if !s.minute[t.Minute()] || !s.hour[t.Hour()] || !s.month[int(t.Month())] {
+		return false
+	}
+	dom, dow := s.dom[t.Day()], s.dow[int(t.Weekday())]
+	switch {
+	case s.domAny && s.dowAny:
+		return true
+	case s.domAny:
+		return dow
+	case s.dowAny:
+		return dom
+	}
+	return dom || dow
+}
+
+// windowOpen returns whether the maintenance window is open at now. If it
+// is closed, it returns when it opens next, or the zero time if it doesn't
+// open within maxWindowDuration.
+func windowOpen(w *apps.MaintenanceWindow, now time.Time) (bool, time.Time, error) {
+	sched, err := parseCron(w.Schedule)
+	if err != nil {
+		return false, time.Time{}, err
+	}
+	loc := time.UTC
+	if w.TimeZone != "" {
+		if loc, err = time.LoadLocation(w.TimeZone); err != nil {
+			return false, time.Time{}, errors.Wrapf(err, "load time zone %q", w.TimeZone)
+		}
+	}
+	now = now.In(loc).Truncate(time.Minute)
+
+	// The window is open if the schedule fired within the last duration.
+	for t := now; now.Sub(t) < w.Duration.Duration; t = t.Add(-time.Minute) {
+		if sched.matches(t) {
+			return true, time.Time{}, nil
+		}
+	}
+	for t := now.Add(time.Minute); t.Sub(now) <= maxWindowDuration; t = t.Add(time.Minute) {
+		if sched.matches(t) {
+			return false, t, nil
+		}
+	}
+	return false, time.Time{}, nil
+}
+
+// gatesOpen returns whether any maintenance window is open. If none is
+// open, it also returns the duration until the next one opens, or
+// maxWindowDuration if none opens before, so that the gates are checked
+// again then.
+func gatesOpen(gates *apps.AppRolloutGates, now time.Time) (bool, time.Duration, error) {
+	if gates == nil || len(gates.MaintenanceWindows) == 0 {
+		return true, 0, nil
+	}
+	var next time.Time
+	for i := range gates.MaintenanceWindows {
+		open, t, err := windowOpen(&gates.MaintenanceWindows[i], now)
+		if err != nil {
+			return false, 0, errors.Wrapf(err, "maintenance window %d", i)
+		}
+		if open {
+			return true, 0, nil
+		}
+		if !t.IsZero() && (next.IsZero() || t.Before(next)) {
+			next = t
+		}
+	}
+	if next.IsZero() {
+		return false, maxWindowDuration, nil
+	}
+	return false, next.Sub(now), nil
+}
+
+// robotHoldReason returns why changes to the robot's ChartAssignment have
+// to be held back, or an empty string if the robot may be updated.
+func robotHoldReason(gates *apps.AppRolloutGates, robot *registry.Robot) string {
+	if robot.Annotations[annotationDoNotDisturb] == "true" {
+		return "robot is annotated with " + annotationDoNotDisturb
+	}
+	if gates == nil || len(gates.RobotStates) == 0 {
+		return ""
+	}
+	st := robot.Status.Robot
+	if st.EmergencyStopButtonPressed {
+		return "emergency stop is pressed"
+	}
+	for _, s := range gates.RobotStates {
+		if string(st.State) == s {
+			return ""
+		}
+	}
+	return fmt.Sprintf("robot state is %q", st.State)
+}
+
+// heldRobotChartAssignments returns the robot ChartAssignments with pending
+// changes that are held back by the rollout's gates along with the reason.
+// It also returns after which duration the maintenance window opens, if
+// changes are held back by it.
+func heldRobotChartAssignments(
+	ar *apps.AppRollout,
+	robotCAs []*apps.ChartAssignment,
+	curCAs map[string]apps.ChartAssignment,
+	robots []registry.Robot,
+	now time.Time,
+) (map[string]string, time.Duration, error) {
+	byName := map[string]*registry.Robot{}
+	for i := range robots {
+		byName[robots[i].Name] = &robots[i]
+	}
+	open, wait, err := gatesOpen(ar.Spec.Gates, now)
+	if err != nil {
+		return nil, 0, err
+	}
+	held := map[string]string{}
+	for _, ca := range robotCAs {
+		if prev, ok := curCAs[ca.Name]; ok {
+			if changed, err := chartAssignmentChanged(&prev, ca); err != nil {
+				return nil, 0, errors.Wrap(err, "check ChartAssignment changed")
+			} else if !changed {
+				continue
+			}
+		}
+		if !open {
+			held[ca.Name] = "outside of maintenance windows"
+			continue
+		}
+		if robot, ok := byName[ca.Labels[labelRobotName]]; ok {
+			if reason := robotHoldReason(ar.Spec.Gates, robot); reason != "" {
+				held[ca.Name] = reason
+			}
+		}
+	}
+	if len(held) == 0 {
+		wait = 0
+	}
+	return held, wait, nil
+}
+
+// setHeldRobots lists the held robots in the status sorted by name. The list
+// is truncated to maxStatusRobots.
+func setHeldRobots(ar *apps.AppRollout, robotCAs []*apps.ChartAssignment, held map[string]string) {
+	var robots []apps.AppRolloutHeldRobot
+	for _, ca := range robotCAs {
+		if reason, ok := held[ca.Name]; ok {
+			robots = append(robots, apps.AppRolloutHeldRobot{
+				Name:   ca.Labels[labelRobotName],
+				Reason: reason,
+			})
+		}
+	}
+	sort.Slice(robots, func(i, j int) bool {
+		return robots[i].Name < robots[j].Name
+	})
+	ar.Status.OmittedHeldRobots = 0
+	if len(robots) > maxStatusRobots {
+		ar.Status.OmittedHeldRobots = int64(len(robots) - maxStatusRobots)
+		robots = robots[:maxStatusRobots]
+	}
+	ar.Status.HeldRobots = robots
+}
+
+// validateGates checks the maintenance window schedules, durations, and
+// time zones.
+func validateGates(gates *apps.AppRolloutGates) error {
+	if gates == nil {
+		return nil
+	}
+	for i, w := range gates.MaintenanceWindows {
+		if w.Duration.Duration <= 0 || w.Duration.Duration > maxWindowDuration {
+			return errors.Errorf("maintenance window %d: duration must be within (0, %s]", i, maxWindowDuration)
+		}
+		if _, _, err := windowOpen(&w, time.Now()); err != nil {
+			return errors.Wrapf(err, "maintenance window %d", i)
+		}
+	}
+	return nil
+}
+
+// robotGateChanged returns true if an update of a robot may change whether
+// its ChartAssignments are held back.
+func robotGateChanged(old, cur kclient.Object) bool {
+	if old.GetAnnotations()[annotationDoNotDisturb] != cur.GetAnnotations()[annotationDoNotDisturb] {
+		return true
+	}
+	oldRobot, ok1 := old.(*registry.Robot)
+	curRobot, ok2 := cur.(*registry.Robot)
+	if !ok1 || !ok2 {
+		return false
+	}
+	o, c := oldRobot.Status.Robot, curRobot.Status.Robot
+	return o.State != c.State || o.EmergencyStopButtonPressed != c.EmergencyStopButtonPressed
+}
@@ -0,0 +1,189 @@
+// Copyright 2026 The Cloud Robotics Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package approllout
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	apps "github.com/SAP/cloud-robotics/src/go/pkg/apis/apps/v1alpha1"
+	registry "github.com/SAP/cloud-robotics/src/go/pkg/apis/registry/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestParseCron(t *testing.T) {
+	cases := []struct {
+		spec       string
+		at         string
+		match      bool
+		shouldFail bool
+	}{
+		{spec: "0 2 * * *", at: "2026-03-04T02:00:00Z", match: true},
+		{spec: "0 2 * * *", at: "2026-03-04T02:01:00Z", match: false},
+		{spec: "*/15 22-23,0-4 * * 1-5", at: "2026-03-04T23:45:00Z", match: true},
+		// 2026-03-07 is a Saturday.
+		{spec: "*/15 22-23,0-4 * * 1-5", at: "2026-03-07T23:45:00Z", match: false},
+		{spec: "0 0 * * 7", at: "2026-03-08T00:00:00Z", match: true},
+		// Restricted day of month and day of week match if either does.
+		{spec: "0 0 1 * 0", at: "2026-04-01T00:00:00Z", match: true},
+		{spec: "0 2 * *", shouldFail: true},
+		{spec: "60 2 * * *", shouldFail: true},
+		{spec: "0 5-2 * * *", shouldFail: true},
+		{spec: "0 */0 * * *", shouldFail: true},
+	}
+	for _, c := range cases {
+		t.Run(c.spec+"@"+c.at, func(t *testing.T) {
+			s, err := parseCron(c.spec)
+			if c.shouldFail {
+				if err == nil {
+					t.Fatal("expected failure but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			at, err := time.Parse(time.RFC3339, c.at)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := s.matches(at); got != c.match {
+				t.Errorf("expected match %v, got %v", c.match, got)
+			}
+		})
+	}
+}
+
+func TestWindowOpen(t *testing.T) {
+	w := &apps.MaintenanceWindow{
+		Schedule: "0 2 * * *",
+		Duration: metav1.Duration{Duration: 2 * time.Hour},
+		TimeZone: "Europe/Berlin",
+	}
+	// 02:30 in Berlin during winter time.
+	open, _, err := windowOpen(w, time.Date(2026, 1, 10, 1, 30, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !open {
+		t.Error("expected window to be open")
+	}
+	now := time.Date(2026, 1, 10, 3, 0, 0, 0, time.UTC)
+	open, next, err := windowOpen(w, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if open {
+		t.Error("expected window to be closed")
+	}
+	if want := time.Date(2026, 1, 11, 1, 0, 0, 0, time.UTC); !next.Equal(want) {
+		t.Errorf("expected window to open at %s, got %s", want, next)
+	}
+}
+
+func TestHeldRobotChartAssignments(t *testing.T) {
+	var ar apps.AppRollout
+	ar.Spec.Gates = &apps.AppRolloutGates{RobotStates: []string{string(registry.RobotStateAvailable)}}
+
+	robots := make([]registry.Robot, 4)
+	for i, ca := range newRobotCAs(4, "1") {
+		robots[i].Name = ca.Labels[labelRobotName]
+		robots[i].Status.Robot.State = registry.RobotStateAvailable
+	}
+	robots[1].Status.Robot.State = registry.RobotStateEmergencyStop
+	robots[2].Annotations = map[string]string{annotationDoNotDisturb: "true"}
+	robots[3].Status.Robot.EmergencyStopButtonPressed = true
+
+	want := newRobotCAs(4, "2")
+	cur := map[string]apps.ChartAssignment{}
+	// Robot 3 is up-to-date already and not reported as held.
+	applyPlan(&rolloutPlan{robots: map[string]bool{want[3].Name: true}}, want, cur, apps.ChartAssignmentPhaseReady)
+
+	held, wait, err := heldRobotChartAssignments(&ar, want, cur, robots, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if wait != 0 {
+		t.Errorf("expected no wait without maintenance windows, got %s", wait)
+	}
+	expected := map[string]string{
+		want[1].Name: `robot state is "EMERGENCY_STOP"`,
+		want[2].Name: "robot is annotated with cloudrobotics.com/do-not-disturb",
+	}
+	if !reflect.DeepEqual(held, expected) {
+		t.Fatalf("expected held robots %v, got %v", expected, held)
+	}
+	setHeldRobots(&ar, want, held)
+	if got := ar.Status.HeldRobots; len(got) != 2 || got[0].Name != "robot01" || got[1].Name != "robot02" {
+		t.Errorf("unexpected held robots in status: %v", got)
+	}
+
+	// Outside of maintenance windows all pending robots are held.
+	ar.Spec.Gates.MaintenanceWindows = []apps.MaintenanceWindow{{
+		Schedule: "0 2 * * *",
+		Duration: metav1.Duration{Duration: time.Hour},
+	}}
+	now := time.Date(2026, 1, 10, 1, 0, 0, 0, time.UTC)
+	held, wait, err = heldRobotChartAssignments(&ar, want, cur, robots, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(held) != 3 {
+		t.Errorf("expected 3 held robots, got %v", held)
+	}
+	if wait != time.Hour {
+		t.Errorf("expected to wait 1h for the window, got %s", wait)
+	}
+}
+
+func TestGatesOpen_checksAgainAfterMaxWindowDuration(t *testing.T) {
+	// The window opens monthly, which is beyond maxWindowDuration.
+	gates := &apps.AppRolloutGates{MaintenanceWindows: []apps.MaintenanceWindow{{
+		Schedule: "0 2 1 * *",
+		Duration: metav1.Duration{Duration: time.Hour},
+	}}}
+	open, wait, err := gatesOpen(gates, time.Date(2026, 1, 10, 1, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if open {
+		t.Error("expected gates to be closed")
+	}
+	if wait != maxWindowDuration {
+		t.Errorf("expected to check again after %s, got %s", maxWindowDuration, wait)
+	}
+}
+
+func TestSetHeldRobots_truncates(t *testing.T) {
+	var ar apps.AppRollout
+	want := newRobotCAs(maxStatusRobots+2, "1")
+	held := map[string]string{}
+	for _, ca := range want {
+		held[ca.Name] = "robot is annotated with " + annotationDoNotDisturb
+	}
+	setHeldRobots(&ar, want, held)
+	if got := len(ar.Status.HeldRobots); got != maxStatusRobots {
+		t.Errorf("expected %d held robots in status, got %d", maxStatusRobots, got)
+	}
+	if got := ar.Status.OmittedHeldRobots; got != 2 {
+		t.Errorf("expected 2 omitted held robots, got %d", got)
+	}
+
+	setHeldRobots(&ar, want, nil)
+	if len(ar.Status.HeldRobots) != 0 || ar.Status.OmittedHeldRobots != 0 {
+		t.Errorf("expected held robots to be cleared, got %+v", ar.Status)
+	}
+}
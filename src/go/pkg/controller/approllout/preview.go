@@ -0,0 +1,208 @@
+// Copyright 2026 The Cloud Robotics Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package approllout
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	apps "github.com/SAP/cloud-robotics/src/go/pkg/apis/apps/v1alpha1"
+	"github.com/pkg/errors"
+)
+
+const (
+	// annotationDryRun on an AppRollout set to "true" stops the controller
+	// from changing its ChartAssignments. Instead the changes that would be
+	// applied are written to the preview in the status.
+	annotationDryRun = "cloudrobotics.com/dry-run"
+
+	// maxPreviewDiff bounds the number of changed fields listed per
+	// ChartAssignment in the preview.
+	maxPreviewDiff = 20
+)
+
+func isDryRun(ar *apps.AppRollout) bool {
+	return ar.Annotations[annotationDryRun] == "true"
+}
+
+// previewChartAssignments compares the wanted ChartAssignments with the
+// current ones and returns the changes that would be applied. It ignores
+// the rollout strategy and gates, i.e. it shows the state once the rollout
+// has completed.
+func previewChartAssignments(revision string, wantCAs []*apps.ChartAssignment, curCAs []apps.ChartAssignment) (*apps.AppRolloutPreview, error) {
+	p := &apps.AppRolloutPreview{Revision: revision}
+	cur := map[string]*apps.ChartAssignment{}
+	for i := range curCAs {
+		cur[curCAs[i].Name] = &curCAs[i]
+	}
+	var changes []apps.AppRolloutPreviewChange
+
+	for _, ca := range wantCAs {
+		prev, ok := cur[ca.Name]
+		delete(cur, ca.Name)
+
+		action := apps.AppRolloutPreviewActionCreate
+		if ok {
+			changed, err := chartAssignmentChanged(prev, ca)
+			if err != nil {
+				return nil, errors.Wrapf(err, "compare ChartAssignment %s", ca.Name)
+			}
+			if !changed {
+				p.Unchanged++
+				continue
+			}
+			action = apps.AppRolloutPreviewActionUpdate
+		}
+		c, err := newPreviewChange(action, prev, ca)
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, c)
+	}
+	for _, ca := range cur {
+		c, err := newPreviewChange(apps.AppRolloutPreviewActionDelete, ca, nil)
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, c)
+	}
+	for _, c := range changes {
+		switch c.Action {
+		case apps.AppRolloutPreviewActionCreate:
+			p.Create++
+		case apps.AppRolloutPreviewActionUpdate:
+			p.Update++
+		case apps.AppRolloutPreviewActionDelete:
+			p.Delete++
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Name < changes[j].Name
+	})
+	if len(changes) > maxStatusRobots {
+		p.OmittedChanges = int64(len(changes) - maxStatusRobots)
+		changes = changes[:maxStatusRobots]
+	}
+	p.Changes = changes
+	return p, nil
+}
+
+// newPreviewChange describes the change from prev to cur. Either may be nil
+// if the ChartAssignment is created or deleted.
+func newPreviewChange(action apps.AppRolloutPreviewAction, prev, cur *apps.ChartAssignment) (apps.AppRolloutPreviewChange, error) {
+	c := apps.AppRolloutPreviewChange{Action: action}
+	for _, ca := range []*apps.ChartAssignment{cur, prev} {
+		if ca != nil {
+			c.Name = ca.Name
+			c.Robot = ca.Labels[labelRobotName]
+			break
+		}
+	}
+	prevFields, err := previewFields(prev)
+	if err != nil {
+		return c, errors.Wrapf(err, "flatten ChartAssignment %s", c.Name)
+	}
+	curFields, err := previewFields(cur)
+	if err != nil {
+		return c, errors.Wrapf(err, "flatten ChartAssignment %s", c.Name)
+	}
+	diff := diffFields(prevFields, curFields)
+	if len(diff) > maxPreviewDiff {
+		c.OmittedDiff = int64(len(diff) - maxPreviewDiff)
+		diff = diff[:maxPreviewDiff]
+	}
+	c.Diff = diff
+	return c, nil
+}
+
+// previewFields flattens the labels, annotations and spec of a
+// ChartAssignment into a map from field paths to JSON-encoded values.
+func previewFields(ca *apps.ChartAssignment) (map[string]string, error) {
+	fields := map[string]string{}
+	if ca == nil {
+		return fields, nil
+	}
+	b, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"labels":      ca.Labels,
+			"annotations": ca.Annotations,
+		},
+		"spec": ca.Spec,
+	})
+	if err != nil {
+		return nil, err
+	}
+	var v interface{}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return nil, err
+	}
+	return fields, flattenJSON("", v, fields)
+}
+
+func flattenJSON(path string, v interface{}, fields map[string]string) error {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, e := range v {
+			p := k
+			if path != "" {
+				p = path + "." + k
+			}
+			if err := flattenJSON(p, e, fields); err != nil {
+				return err
+			}
+		}
+		return nil
+	case []interface{}:
+		if len(v) == 0 {
+			fields[path] = "[]"
+		}
+		for i, e := range v {
+			if err := flattenJSON(fmt.Sprintf("%s[%d]", path, i), e, fields); err != nil {
+				return err
+			}
+		}
+		return nil
+	case nil:
+		// Unset labels, annotations and values are omitted.
+		return nil
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	fields[path] = string(b)
+	return nil
+}
+
+// diffFields returns the fields that differ between prev and cur sorted by
+// path.
+func diffFields(prev, cur map[string]string) []apps.AppRolloutPreviewField {
+	var diff []apps.AppRolloutPreviewField
+	for p, old := range prev {
+		if n, ok := cur[p]; !ok || n != old {
+			diff = append(diff, apps.AppRolloutPreviewField{Path: p, Old: old, New: n})
+		}
+	}
+	for p, n := range cur {
+		if _, ok := prev[p]; !ok {
+			diff = append(diff, apps.AppRolloutPreviewField{Path: p, New: n})
+		}
+	}
+	sort.Slice(diff, func(i, j int) bool {
+		return diff[i].Path < diff[j].Path
+	})
+	return diff
+}
@@ -0,0 +1,145 @@
+// Copyright 2026 The Cloud Robotics Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package approllout
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	apps "github.com/SAP/cloud-robotics/src/go/pkg/apis/apps/v1alpha1"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestPreviewChartAssignments(t *testing.T) {
+	newCA := func(name, robot string, values apps.ConfigValues) *apps.ChartAssignment {
+		ca := &apps.ChartAssignment{}
+		ca.Name = name
+		if robot != "" {
+			setLabel(&ca.ObjectMeta, labelRobotName, robot)
+		}
+		ca.Spec.Chart.Inline = "inline"
+		ca.Spec.Chart.Values = values
+		return ca
+	}
+	cur := []apps.ChartAssignment{
+		*newCA("foo-robot-robot1", "robot1", apps.ConfigValues{"a": "1", "b": map[string]interface{}{"c": "2"}}),
+		*newCA("foo-robot-robot2", "robot2", apps.ConfigValues{"a": "1"}),
+		*newCA("foo-robot-robot3", "robot3", apps.ConfigValues{"a": "1"}),
+	}
+	want := []*apps.ChartAssignment{
+		newCA("foo-robot-robot1", "robot1", apps.ConfigValues{"a": "2", "b": map[string]interface{}{"d": "3"}}),
+		newCA("foo-robot-robot2", "robot2", apps.ConfigValues{"a": "1"}),
+		newCA("foo-robot-robot4", "robot4", nil),
+	}
+	p, err := previewChartAssignments("rev", want, cur)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Create != 1 || p.Update != 1 || p.Delete != 1 || p.Unchanged != 1 {
+		t.Errorf("unexpected counts %+v", p)
+	}
+	if len(p.Changes) != 3 {
+		t.Fatalf("expected 3 changes, got %+v", p.Changes)
+	}
+	update := p.Changes[0]
+	if update.Robot != "robot1" || update.Action != apps.AppRolloutPreviewActionUpdate {
+		t.Errorf("expected update of robot1 first, got %+v", update)
+	}
+	wantDiff := []apps.AppRolloutPreviewField{
+		{Path: "spec.chart.values.a", Old: `"1"`, New: `"2"`},
+		{Path: "spec.chart.values.b.c", Old: `"2"`},
+		{Path: "spec.chart.values.b.d", New: `"3"`},
+	}
+	if !reflect.DeepEqual(update.Diff, wantDiff) {
+		t.Errorf("expected diff %+v, got %+v", wantDiff, update.Diff)
+	}
+	if c := p.Changes[1]; c.Robot != "robot3" || c.Action != apps.AppRolloutPreviewActionDelete {
+		t.Errorf("expected deletion of robot3, got %+v", c)
+	}
+	if c := p.Changes[2]; c.Robot != "robot4" || c.Action != apps.AppRolloutPreviewActionCreate {
+		t.Errorf("expected creation of robot4, got %+v", c)
+	}
+}
+
+func TestReconcile_dryRun(t *testing.T) {
+	ctx := context.Background()
+
+	var app apps.App
+	unmarshalYAML(t, &app, `
+metadata:
+  name: foo
+spec:
+  components:
+    robot:
+      inline: inline-robot
+	`)
+	var ar apps.AppRollout
+	unmarshalYAML(t, &ar, `
+metadata:
+  name: foo
+  namespace: default
+  annotations:
+    cloudrobotics.com/dry-run: "true"
+spec:
+  appName: foo
+  robots:
+  - selector:
+      matchLabels:
+        fleet: a
+	`)
+	r := &Reconciler{kube: newFakeClient(append(newFleet(4), &app, &ar)...)}
+
+	reconcileOnce := func() *apps.AppRollout {
+		var cur apps.AppRollout
+		if err := r.kube.Get(ctx, kclient.ObjectKeyFromObject(&ar), &cur); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := r.reconcile(ctx, &cur); err != nil {
+			t.Fatal(err)
+		}
+		if err := r.kube.Get(ctx, kclient.ObjectKeyFromObject(&ar), &cur); err != nil {
+			t.Fatal(err)
+		}
+		return &cur
+	}
+	numCAs := func() int {
+		var cas apps.ChartAssignmentList
+		if err := r.kube.List(ctx, &cas); err != nil {
+			t.Fatal(err)
+		}
+		return len(cas.Items)
+	}
+
+	cur := reconcileOnce()
+	if n := numCAs(); n != 0 {
+		t.Errorf("expected no ChartAssignments in dry run, got %d", n)
+	}
+	if p := cur.Status.Preview; p == nil || p.Create != 2 {
+		t.Fatalf("expected preview with 2 creations, got %+v", p)
+	}
+
+	delete(cur.Annotations, annotationDryRun)
+	if err := r.kube.Update(ctx, cur); err != nil {
+		t.Fatal(err)
+	}
+	cur = reconcileOnce()
+	if n := numCAs(); n != 2 {
+		t.Errorf("expected 2 ChartAssignments, got %d", n)
+	}
+	if cur.Status.Preview != nil {
+		t.Errorf("expected preview to be cleared, got %+v", cur.Status.Preview)
+	}
+}
@@ -0,0 +1,144 @@
+// Copyright 2026 The Cloud Robotics Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package approllout
+
+import (
+	"context"
+	"log"
+
+	apps "github.com/SAP/cloud-robotics/src/go/pkg/apis/apps/v1alpha1"
+	"github.com/SAP/cloud-robotics/src/go/pkg/controller/apprelease"
+	"github.com/pkg/errors"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const fieldIndexReleaseName = "spec.releaseName"
+
+type errRelease string
+
+func (e errRelease) Error() string {
+	return string(e)
+}
+
+// getRelease retrieves the AppRelease referenced by the rollout. It returns
+// nil if the rollout doesn't reference one.
+func getRelease(ctx context.Context, kube kclient.Client, ar *apps.AppRollout) (*apps.AppRelease, error) {
+	if ar.Spec.ReleaseName == "" {
+		return nil, nil
+	}
+	var rel apps.AppRelease
+	err := kube.Get(ctx, kclient.ObjectKey{Namespace: ar.Namespace, Name: ar.Spec.ReleaseName}, &rel)
+	if k8serrors.IsNotFound(err) {
+		return nil, errRelease("AppRelease " + ar.Spec.ReleaseName + " not found")
+	} else if err != nil {
+		return nil, errors.Wrapf(err, "get AppRelease %q", ar.Spec.ReleaseName)
+	}
+	if rel.Spec.AppName != ar.Spec.AppName {
+		return nil, errRelease("AppRelease " + rel.Name + " is for app " + rel.Spec.AppName)
+	}
+	return &rel, nil
+}
+
+// resolveChannels returns copies of the app and rollout in which channels
+// are replaced with the versions they point to in the release. The app's
+// version is set from the rollout's channel and robot versions from their
+// entry's channel.
+func resolveChannels(app *apps.App, ar *apps.AppRollout, rel *apps.AppRelease) (*apps.App, *apps.AppRollout, error) {
+	app, ar = app.DeepCopy(), ar.DeepCopy()
+	if rel == nil {
+		return app, ar, nil
+	}
+	version := func(channel string) (string, error) {
+		v, ok := apprelease.ChannelVersion(rel, channel)
+		if !ok {
+			return "", errRelease("channel " + channel + " not found in AppRelease " + rel.Name)
+		}
+		return v, nil
+	}
+	if ar.Spec.Channel != "" {
+		v, err := version(ar.Spec.Channel)
+		if err != nil {
+			return nil, nil, err
+		}
+		app.Spec.Version = v
+	}
+	for i := range ar.Spec.Robots {
+		r := &ar.Spec.Robots[i]
+		if r.Channel == "" {
+			continue
+		}
+		v, err := version(r.Channel)
+		if err != nil {
+			return nil, nil, err
+		}
+		r.Version = v
+		r.Channel = ""
+	}
+	return app, ar, nil
+}
+
+// validateChannels checks that channels are only used along with a release
+// and don't conflict with literal versions.
+func validateChannels(spec *apps.AppRolloutSpec) error {
+	if spec.ReleaseName == "" {
+		if spec.Channel != "" {
+			return errors.New("channel requires releaseName")
+		}
+		for i, r := range spec.Robots {
+			if r.Channel != "" {
+				return errors.Errorf("channel for robots %d requires releaseName", i)
+			}
+		}
+		return nil
+	}
+	for i, r := range spec.Robots {
+		if r.Channel != "" && r.Version != "" {
+			return errors.Errorf("robots %d must not set both version and channel", i)
+		}
+	}
+	return nil
+}
+
+func indexReleaseName(o kclient.Object) []string {
+	ar := o.(*apps.AppRollout)
+	if ar.Spec.ReleaseName == "" {
+		return nil
+	}
+	return []string{ar.Spec.ReleaseName}
+}
+
+// enqueueForRelease enqueues all AppRollouts that reference the given
+// AppRelease.
+func (r *Reconciler) enqueueForRelease(ctx context.Context, m metav1.Object, q workqueue.RateLimitingInterface) {
+	var rollouts apps.AppRolloutList
+	err := r.kube.List(ctx, &rollouts,
+		kclient.InNamespace(m.GetNamespace()),
+		kclient.MatchingFields(map[string]string{fieldIndexReleaseName: m.GetName()}),
+	)
+	if err != nil {
+		log.Printf("List AppRollouts for release %s/%s failed: %s", m.GetNamespace(), m.GetName(), err)
+		return
+	}
+	for _, ar := range rollouts.Items {
+		q.Add(reconcile.Request{
+			NamespacedName: types.NamespacedName{Name: ar.Name, Namespace: ar.Namespace},
+		})
+	}
+}
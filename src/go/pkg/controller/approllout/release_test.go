@@ -0,0 +1,83 @@
+// Copyright 2026 The Cloud Robotics Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package approllout
+
+import (
+	"testing"
+
+	apps "github.com/SAP/cloud-robotics/src/go/pkg/apis/apps/v1alpha1"
+)
+
+func TestResolveChannels(t *testing.T) {
+	var app apps.App
+	unmarshalYAML(t, &app, `
+metadata:
+  name: foo
+spec:
+  version: 1.0.0
+	`)
+	var ar apps.AppRollout
+	unmarshalYAML(t, &ar, `
+metadata:
+  name: foo
+  namespace: default
+spec:
+  appName: foo
+  releaseName: foo
+  channel: production
+  robots:
+  - selector:
+      matchLabels:
+        stage: staging
+    channel: staging
+  - selector:
+      any: true
+	`)
+	var rel apps.AppRelease
+	unmarshalYAML(t, &rel, `
+metadata:
+  name: foo
+  namespace: default
+spec:
+  appName: foo
+  channels:
+  - name: staging
+    version: 1.4.0
+  - name: production
+    version: 1.3.0
+	`)
+	resolvedApp, resolvedAr, err := resolveChannels(&app, &ar, &rel)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := resolvedApp.Spec.Version; got != "1.3.0" {
+		t.Errorf("expected app version of production channel, got %q", got)
+	}
+	if got := resolvedAr.Spec.Robots[0].Version; got != "1.4.0" {
+		t.Errorf("expected robot version of staging channel, got %q", got)
+	}
+	if got := resolvedAr.Spec.Robots[1].Version; got != "" {
+		t.Errorf("expected no version override, got %q", got)
+	}
+	// The inputs are left unchanged.
+	if app.Spec.Version != "1.0.0" || ar.Spec.Robots[0].Channel != "staging" {
+		t.Error("expected app and rollout not to be modified")
+	}
+
+	ar.Spec.Channel = "canary"
+	if _, _, err := resolveChannels(&app, &ar, &rel); err == nil {
+		t.Error("expected error for unknown channel")
+	}
+}
@@ -0,0 +1,240 @@
+// Copyright 2026 The Cloud Robotics Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package approllout
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"log"
+	"sort"
+
+	apps "github.com/SAP/cloud-robotics/src/go/pkg/apis/apps/v1alpha1"
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	core "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// labelRolloutName is set on the ControllerRevisions of an AppRollout.
+	labelRolloutName = "cloudrobotics.com/approllout-name"
+
+	defaultRevisionHistoryLimit = 10
+)
+
+// revisionData is the part of the app and rollout spec that determines the
+// generated ChartAssignments. It is stored in ControllerRevisions so that
+// the rollout can be reverted to it.
+type revisionData struct {
+	App     apps.AppSpec        `json:"app"`
+	Rollout apps.AppRolloutSpec `json:"rollout"`
+}
+
+// rolloutRevision returns the revision data for the app and rollout and a
+// hash identifying it. The strategy and rollback settings are excluded so
+// that changing them doesn't start a new revision.
+func rolloutRevision(app *apps.App, ar *apps.AppRollout) (string, *revisionData, error) {
+	data := &revisionData{App: *app.Spec.DeepCopy(), Rollout: *ar.Spec.DeepCopy()}
+	data.Rollout.Strategy = nil
+	data.Rollout.Rollback = nil
+	data.Rollout.RevisionHistoryLimit = nil
+
+	b, err := json.Marshal(data)
+	if err != nil {
+		return "", nil, err
+	}
+	h := fnv.New64a()
+	h.Write(b)
+	return fmt.Sprintf("%016x", h.Sum64()), data, nil
+}
+
+func controllerRevisionName(rollout, revision string) string {
+	return fmt.Sprintf("%s-%s", rollout, revision)
+}
+
+// ensureRevision stores the revision data in a ControllerRevision owned by
+// the rollout and prunes revisions exceeding the history limit. The ready
+// revision is never pruned.
+func (r *Reconciler) ensureRevision(ctx context.Context, ar *apps.AppRollout, revision string, data *revisionData) error {
+	var revs appsv1.ControllerRevisionList
+	err := r.kube.List(ctx, &revs, kclient.InNamespace(ar.Namespace), kclient.MatchingLabels{labelRolloutName: ar.Name})
+	if err != nil {
+		return errors.Wrap(err, "list ControllerRevisions")
+	}
+	name := controllerRevisionName(ar.Name, revision)
+	var latest int64
+	found := false
+	for _, cr := range revs.Items {
+		if cr.Name == name {
+			found = true
+		}
+		if cr.Revision > latest {
+			latest = cr.Revision
+		}
+	}
+	if !found {
+		b, err := json.Marshal(data)
+		if err != nil {
+			return errors.Wrap(err, "encode revision")
+		}
+		_true := true
+		cr := appsv1.ControllerRevision{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: ar.Namespace,
+				Name:      name,
+				Labels:    map[string]string{labelRolloutName: ar.Name},
+			},
+			Data:     runtime.RawExtension{Raw: b},
+			Revision: latest + 1,
+		}
+		setOwnerReference(&cr.ObjectMeta, metav1.OwnerReference{
+			APIVersion:         ar.APIVersion,
+			Kind:               ar.Kind,
+			Name:               ar.Name,
+			UID:                ar.UID,
+			BlockOwnerDeletion: &_true,
+			Controller:         &_true,
+		})
+		if err := r.kube.Create(ctx, &cr); err != nil && !k8serrors.IsAlreadyExists(err) {
+			return errors.Wrapf(err, "create ControllerRevision %s/%s", cr.Namespace, cr.Name)
+		}
+		revs.Items = append(revs.Items, cr)
+	}
+	limit := defaultRevisionHistoryLimit
+	if ar.Spec.RevisionHistoryLimit != nil {
+		limit = int(*ar.Spec.RevisionHistoryLimit)
+	}
+	if len(revs.Items) <= limit {
+		return nil
+	}
+	sort.Slice(revs.Items, func(i, j int) bool {
+		return revs.Items[i].Revision < revs.Items[j].Revision
+	})
+	keep := map[string]bool{
+		name: true,
+		controllerRevisionName(ar.Name, ar.Status.ReadyRevision): true,
+	}
+	remaining := len(revs.Items)
+	for _, cr := range revs.Items {
+		if remaining <= limit {
+			break
+		}
+		if keep[cr.Name] {
+			continue
+		}
+		if err := r.kube.Delete(ctx, &cr); err != nil && !k8serrors.IsNotFound(err) {
+			return errors.Wrapf(err, "delete ControllerRevision %s/%s", cr.Namespace, cr.Name)
+		}
+		remaining--
+	}
+	return nil
+}
+
+// getRevision returns the revision data stored for the rollout.
+func (r *Reconciler) getRevision(ctx context.Context, ar *apps.AppRollout, revision string) (*revisionData, error) {
+	var cr appsv1.ControllerRevision
+	key := kclient.ObjectKey{Namespace: ar.Namespace, Name: controllerRevisionName(ar.Name, revision)}
+	if err := r.kube.Get(ctx, key, &cr); err != nil {
+		return nil, errors.Wrapf(err, "get ControllerRevision %s", key)
+	}
+	var data revisionData
+	if err := json.Unmarshal(cr.Data.Raw, &data); err != nil {
+		return nil, errors.Wrapf(err, "decode ControllerRevision %s", key)
+	}
+	return &data, nil
+}
+
+// updateRevisionStatus records the current revision, marks it as ready if
+// all wanted ChartAssignments are ready, and rolls back to the ready revision
+// if more ChartAssignments failed than the rollback threshold allows. It
+// returns true if the current revision is rolled back.
+func updateRevisionStatus(
+	ar *apps.AppRollout,
+	revision string,
+	wantCAs []*apps.ChartAssignment,
+	curCAs map[string]apps.ChartAssignment,
+) (bool, error) {
+	if rb := ar.Status.RolledBackRevision; rb != "" && rb != revision {
+		// The spec changed since the rollback.
+		setCondition(ar, apps.AppRolloutConditionRolledBack, core.ConditionFalse, "")
+		ar.Status.RolledBackRevision = ""
+	}
+	ar.Status.CurrentRevision = revision
+
+	if ar.Status.RolledBackRevision == revision {
+		return true, nil
+	}
+	var ready, failed int
+	for _, ca := range wantCAs {
+		prev, ok := curCAs[ca.Name]
+		if !ok {
+			continue
+		}
+		if changed, err := chartAssignmentChanged(&prev, ca); err != nil {
+			return false, errors.Wrap(err, "check ChartAssignment changed")
+		} else if changed {
+			continue
+		}
+		if chartAssignmentReady(&prev) {
+			ready++
+		} else if chartAssignmentFailed(&prev) {
+			failed++
+		}
+	}
+	if ready == len(wantCAs) {
+		ar.Status.ReadyRevision = revision
+		return false, nil
+	}
+	rb := ar.Spec.Rollback
+	if rb == nil || ar.Status.ReadyRevision == "" || ar.Status.ReadyRevision == revision {
+		return false, nil
+	}
+	threshold, err := scaledValue(rb.FailureThreshold, len(wantCAs), false, 0)
+	if err != nil {
+		return false, errors.Wrap(err, "failure threshold")
+	}
+	if failed <= threshold {
+		return false, nil
+	}
+	msg := fmt.Sprintf("%d/%d ChartAssignments of revision %s failed, rolled back to revision %s",
+		failed, len(wantCAs), revision, ar.Status.ReadyRevision)
+	log.Printf("AppRollout %s/%s: %s", ar.Namespace, ar.Name, msg)
+
+	ar.Status.RolledBackRevision = revision
+	setCondition(ar, apps.AppRolloutConditionRolledBack, core.ConditionTrue, msg)
+	return true, nil
+}
+
+// validateRollback checks the rollback threshold and revision history limit.
+func validateRollback(spec *apps.AppRolloutSpec) error {
+	if rb := spec.Rollback; rb != nil {
+		n, err := scaledValue(rb.FailureThreshold, 100, false, 0)
+		if err != nil {
+			return errors.Wrap(err, "invalid failureThreshold")
+		}
+		if n < 0 {
+			return errors.New("failureThreshold must not be negative")
+		}
+	}
+	if l := spec.RevisionHistoryLimit; l != nil && *l < 0 {
+		return errors.New("revisionHistoryLimit must not be negative")
+	}
+	return nil
+}
@@ -0,0 +1,224 @@
+// Copyright 2026 The Cloud Robotics Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package approllout
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	apps "github.com/SAP/cloud-robotics/src/go/pkg/apis/apps/v1alpha1"
+	"github.com/SAP/cloud-robotics/src/go/pkg/controller/chartassignment"
+	appsv1 "k8s.io/api/apps/v1"
+	core "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes/scheme"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestRolloutRevision_ignoresStrategy(t *testing.T) {
+	var app apps.App
+	app.Spec.Version = "1.0.0"
+	var ar apps.AppRollout
+	ar.Spec.AppName = "foo"
+
+	rev1, _, err := rolloutRevision(&app, &ar)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ar.Spec.Strategy = &apps.AppRolloutStrategy{Paused: true}
+	rev2, _, err := rolloutRevision(&app, &ar)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rev1 != rev2 {
+		t.Errorf("expected strategy not to change revision, got %s and %s", rev1, rev2)
+	}
+	app.Spec.Version = "1.0.1"
+	rev3, _, err := rolloutRevision(&app, &ar)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rev1 == rev3 {
+		t.Errorf("expected app version to change revision")
+	}
+}
+
+func TestEnsureRevision_prunesHistory(t *testing.T) {
+	ctx := context.Background()
+	sc := runtime.NewScheme()
+	scheme.AddToScheme(sc)
+	apps.AddToScheme(sc)
+	r := &Reconciler{kube: fake.NewClientBuilder().WithScheme(sc).Build()}
+
+	limit := int32(2)
+	ar := &apps.AppRollout{}
+	ar.Namespace = "default"
+	ar.Name = "foo"
+	ar.Spec.RevisionHistoryLimit = &limit
+	ar.Status.ReadyRevision = "rev0"
+
+	for i := 0; i < 4; i++ {
+		rev := fmt.Sprintf("rev%d", i)
+		if err := r.ensureRevision(ctx, ar, rev, &revisionData{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var revs appsv1.ControllerRevisionList
+	if err := r.kube.List(ctx, &revs, kclient.MatchingLabels{labelRolloutName: "foo"}); err != nil {
+		t.Fatal(err)
+	}
+	got := map[string]int64{}
+	for _, cr := range revs.Items {
+		got[cr.Name] = cr.Revision
+	}
+	// The ready revision is kept in addition to the newest ones.
+	want := map[string]int64{"foo-rev0": 1, "foo-rev3": 4}
+	if len(got) != len(want) {
+		t.Fatalf("expected revisions %v, got %v", want, got)
+	}
+	for name, rev := range want {
+		if got[name] != rev {
+			t.Errorf("expected revision %s to have number %d, got %v", name, rev, got)
+		}
+	}
+	data, err := r.getRevision(ctx, ar, "rev0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data == nil {
+		t.Fatal("expected revision data")
+	}
+}
+
+func TestUpdateRevisionStatus(t *testing.T) {
+	one := intstr.FromInt(1)
+	ar := &apps.AppRollout{}
+	ar.Spec.Rollback = &apps.AppRolloutRollback{FailureThreshold: &one}
+
+	want := newRobotCAs(3, "1")
+	cur := map[string]apps.ChartAssignment{}
+	applyPlan(&rolloutPlan{all: true}, want, cur, apps.ChartAssignmentPhaseReady)
+
+	if rolledBack, err := updateRevisionStatus(ar, "rev1", want, cur); err != nil {
+		t.Fatal(err)
+	} else if rolledBack {
+		t.Fatal("unexpected rollback")
+	}
+	if ar.Status.ReadyRevision != "rev1" {
+		t.Fatalf("expected ready revision rev1, got %q", ar.Status.ReadyRevision)
+	}
+
+	// Roll out a new version of which a single robot fails, which is within
+	// the threshold.
+	want = newRobotCAs(3, "2")
+	applyPlan(&rolloutPlan{all: true}, want, cur, apps.ChartAssignmentPhaseSettled)
+	applyPlan(&rolloutPlan{robots: map[string]bool{want[0].Name: true}}, want, cur, apps.ChartAssignmentPhaseFailed)
+
+	if rolledBack, err := updateRevisionStatus(ar, "rev2", want, cur); err != nil {
+		t.Fatal(err)
+	} else if rolledBack {
+		t.Fatal("unexpected rollback below threshold")
+	}
+
+	// A second failure exceeds the threshold.
+	applyPlan(&rolloutPlan{robots: map[string]bool{want[1].Name: true}}, want, cur, apps.ChartAssignmentPhaseFailed)
+
+	if rolledBack, err := updateRevisionStatus(ar, "rev2", want, cur); err != nil {
+		t.Fatal(err)
+	} else if !rolledBack {
+		t.Fatal("expected rollback")
+	}
+	if ar.Status.RolledBackRevision != "rev2" || ar.Status.ReadyRevision != "rev1" {
+		t.Errorf("unexpected revision status %+v", ar.Status)
+	}
+	if c := ar.Status.Conditions[0]; c.Type != apps.AppRolloutConditionRolledBack || c.Status != core.ConditionTrue {
+		t.Errorf("expected RolledBack condition, got %+v", c)
+	}
+
+	// The rollback sticks until the spec changes.
+	if rolledBack, err := updateRevisionStatus(ar, "rev2", want, cur); err != nil {
+		t.Fatal(err)
+	} else if !rolledBack {
+		t.Fatal("expected rollback to stick")
+	}
+	if rolledBack, err := updateRevisionStatus(ar, "rev3", newRobotCAs(3, "3"), cur); err != nil {
+		t.Fatal(err)
+	} else if rolledBack {
+		t.Fatal("expected rollback to be cleared for new revision")
+	}
+	if c := ar.Status.Conditions[0]; c.Status != core.ConditionFalse {
+		t.Errorf("expected RolledBack condition to be false, got %+v", c)
+	}
+}
+
+// syncRobotStatus sets the status of the ChartAssignments as it's synced
+// from the robots, whose generations differ from the cloud's.
+func syncRobotStatus(cur map[string]apps.ChartAssignment, phase apps.ChartAssignmentPhase, names ...string) {
+	for _, name := range names {
+		ca := cur[name]
+		ca.Generation += 3
+		ca.Status.ObservedGeneration = 1
+		ca.Status.ObservedSpecHash = chartassignment.SpecHash(&ca.Spec)
+		ca.Status.Phase = phase
+		cur[name] = ca
+	}
+}
+
+func TestUpdateRevisionStatus_robotGenerationsDiffer(t *testing.T) {
+	zero := intstr.FromInt(0)
+	ar := &apps.AppRollout{}
+	ar.Spec.Rollback = &apps.AppRolloutRollback{FailureThreshold: &zero}
+
+	want := newRobotCAs(2, "1")
+	cur := map[string]apps.ChartAssignment{}
+	applyPlan(&rolloutPlan{all: true}, want, cur, "")
+	syncRobotStatus(cur, apps.ChartAssignmentPhaseReady, want[0].Name, want[1].Name)
+
+	if _, err := updateRevisionStatus(ar, "rev1", want, cur); err != nil {
+		t.Fatal(err)
+	}
+	if ar.Status.ReadyRevision != "rev1" {
+		t.Fatalf("expected ready revision rev1, got %q", ar.Status.ReadyRevision)
+	}
+
+	// The status of the new version isn't synced yet, so the robots still
+	// report the hash of the previous spec.
+	prevHash := cur[want[0].Name].Status.ObservedSpecHash
+	want = newRobotCAs(2, "2")
+	applyPlan(&rolloutPlan{all: true}, want, cur, apps.ChartAssignmentPhaseFailed)
+	for _, ca := range want {
+		c := cur[ca.Name]
+		c.Status.ObservedSpecHash = prevHash
+		cur[ca.Name] = c
+	}
+	if rolledBack, err := updateRevisionStatus(ar, "rev2", want, cur); err != nil {
+		t.Fatal(err)
+	} else if rolledBack {
+		t.Fatal("unexpected rollback for status of the previous spec")
+	}
+
+	syncRobotStatus(cur, apps.ChartAssignmentPhaseFailed, want[0].Name)
+	if rolledBack, err := updateRevisionStatus(ar, "rev2", want, cur); err != nil {
+		t.Fatal(err)
+	} else if !rolledBack {
+		t.Fatal("expected rollback")
+	}
+	if ar.Status.ReadyRevision != "rev1" {
+		t.Errorf("expected ready revision rev1, got %q", ar.Status.ReadyRevision)
+	}
+}
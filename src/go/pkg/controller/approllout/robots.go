@@ -0,0 +1,128 @@
+// Copyright 2026 The Cloud Robotics Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package approllout
+
+import (
+	"context"
+	"log"
+	"sort"
+
+	apps "github.com/SAP/cloud-robotics/src/go/pkg/apis/apps/v1alpha1"
+	registry "github.com/SAP/cloud-robotics/src/go/pkg/apis/registry/v1alpha1"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// selectsAny returns true if the selector matches all robots.
+func selectsAny(sel *apps.RobotSelector) bool {
+	return sel != nil && sel.Any != nil && *sel.Any
+}
+
+// listSelectedRobots lists the robots in the rollout's namespace that are
+// selected by any of its entries. Label selectors are passed to the client
+// so that only matching robots are retrieved.
+func listSelectedRobots(ctx context.Context, kube kclient.Client, ar *apps.AppRollout) ([]registry.Robot, error) {
+	for _, e := range ar.Spec.Robots {
+		if selectsAny(e.Selector) {
+			var robots registry.RobotList
+			if err := kube.List(ctx, &robots, kclient.InNamespace(ar.Namespace)); err != nil {
+				return nil, errors.Wrap(err, "list all Robots")
+			}
+			return robots.Items, nil
+		}
+	}
+	var (
+		res  []registry.Robot
+		seen = map[string]bool{}
+	)
+	for i, e := range ar.Spec.Robots {
+		if e.Selector == nil || e.Selector.LabelSelector == nil {
+			continue
+		}
+		sel, err := metav1.LabelSelectorAsSelector(e.Selector.LabelSelector)
+		if err != nil {
+			return nil, errors.Wrapf(err, "selector for robots %d", i)
+		}
+		var robots registry.RobotList
+		err = kube.List(ctx, &robots, kclient.InNamespace(ar.Namespace), kclient.MatchingLabelsSelector{Selector: sel})
+		if err != nil {
+			return nil, errors.Wrapf(err, "list Robots for selector %q", sel)
+		}
+		for _, r := range robots.Items {
+			if !seen[r.Name] {
+				seen[r.Name] = true
+				res = append(res, r)
+			}
+		}
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Name < res[j].Name
+	})
+	return res, nil
+}
+
+// rolloutSelects returns true if any entry or the cloud cluster selector of
+// the rollout selects a robot with the given labels. Invalid selectors are
+// treated as selecting the robot so that the rollout is reconciled and
+// reports the error.
+func rolloutSelects(ar *apps.AppRollout, robotLabels map[string]string) bool {
+	if cs := ar.Spec.Cloud.ClusterSelector; cs != nil {
+		sel, err := metav1.LabelSelectorAsSelector(cs)
+		if err != nil || sel.Matches(labels.Set(robotLabels)) {
+			return true
+		}
+	}
+	for _, e := range ar.Spec.Robots {
+		if selectsAny(e.Selector) {
+			return true
+		}
+		if e.Selector == nil || e.Selector.LabelSelector == nil {
+			continue
+		}
+		sel, err := metav1.LabelSelectorAsSelector(e.Selector.LabelSelector)
+		if err != nil || sel.Matches(labels.Set(robotLabels)) {
+			return true
+		}
+	}
+	return false
+}
+
+// enqueueForRobot enqueues the AppRollouts in the namespace that select a
+// robot with any of the given label sets, e.g. the labels before and after
+// an update.
+func (r *Reconciler) enqueueForRobot(ctx context.Context, namespace string, q workqueue.RateLimitingInterface, robotLabels ...map[string]string) {
+	var rollouts apps.AppRolloutList
+	err := r.kube.List(ctx, &rollouts, kclient.InNamespace(namespace))
+	if err != nil {
+		log.Printf("List AppRollouts failed: %s", err)
+		return
+	}
+	for i := range rollouts.Items {
+		ar := &rollouts.Items[i]
+		for _, l := range robotLabels {
+			if rolloutSelects(ar, l) {
+				q.Add(reconcile.Request{
+					NamespacedName: types.NamespacedName{Name: ar.Name, Namespace: ar.Namespace},
+				})
+				break
+			}
+		}
+	}
+}
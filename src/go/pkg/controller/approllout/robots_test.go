@@ -0,0 +1,178 @@
+// Copyright 2026 The Cloud Robotics Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package approllout
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	apps "github.com/SAP/cloud-robotics/src/go/pkg/apis/apps/v1alpha1"
+	config "github.com/SAP/cloud-robotics/src/go/pkg/apis/config/v1alpha1"
+	registry "github.com/SAP/cloud-robotics/src/go/pkg/apis/registry/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/util/workqueue"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// newFleet returns n robots in the default namespace. Every robot is
+// labelled with its fleet, which alternates between "a" and "b".
+func newFleet(n int) []kclient.Object {
+	var objs []kclient.Object
+	for i := 0; i < n; i++ {
+		r := &registry.Robot{}
+		r.Namespace = "default"
+		r.Name = fmt.Sprintf("robot%04d", i)
+		r.Labels = map[string]string{"fleet": string(rune('a' + i%2))}
+		objs = append(objs, r)
+	}
+	return objs
+}
+
+func newFakeClient(objs ...kclient.Object) kclient.Client {
+	sc := runtime.NewScheme()
+	scheme.AddToScheme(sc)
+	apps.AddToScheme(sc)
+	config.AddToScheme(sc)
+	registry.AddToScheme(sc)
+	return fake.NewClientBuilder().WithScheme(sc).WithObjects(objs...).Build()
+}
+
+func TestListSelectedRobots(t *testing.T) {
+	kube := newFakeClient(newFleet(6)...)
+
+	var ar apps.AppRollout
+	unmarshalYAML(t, &ar, `
+metadata:
+  name: foo
+  namespace: default
+spec:
+  appName: foo
+  robots:
+  - selector:
+      matchLabels:
+        fleet: a
+  - selector:
+      matchExpressions:
+      - {key: fleet, operator: In, values: [a]}
+	`)
+	robots, err := listSelectedRobots(context.Background(), kube, &ar)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, r := range robots {
+		names = append(names, r.Name)
+	}
+	if want := []string{"robot0000", "robot0002", "robot0004"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("expected robots %v, got %v", want, names)
+	}
+
+	_true := true
+	ar.Spec.Robots[1].Selector = &apps.RobotSelector{Any: &_true}
+	robots, err = listSelectedRobots(context.Background(), kube, &ar)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(robots) != 6 {
+		t.Errorf("expected all 6 robots, got %d", len(robots))
+	}
+}
+
+func TestEnqueueForRobot(t *testing.T) {
+	var fleetA, fleetB apps.AppRollout
+	unmarshalYAML(t, &fleetA, `
+metadata:
+  name: fleet-a
+  namespace: default
+spec:
+  appName: foo
+  robots:
+  - selector:
+      matchLabels:
+        fleet: a
+	`)
+	unmarshalYAML(t, &fleetB, `
+metadata:
+  name: fleet-b
+  namespace: default
+spec:
+  appName: foo
+  robots:
+  - selector:
+      matchLabels:
+        fleet: b
+	`)
+	r := &Reconciler{kube: newFakeClient(&fleetA, &fleetB)}
+
+	cases := []struct {
+		name   string
+		labels []map[string]string
+		want   int
+	}{
+		{name: "unselected", labels: []map[string]string{{"fleet": "c"}}, want: 0},
+		{name: "selected", labels: []map[string]string{{"fleet": "a"}}, want: 1},
+		{name: "moved", labels: []map[string]string{{"fleet": "a"}, {"fleet": "b"}}, want: 2},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			q := workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter())
+			defer q.ShutDown()
+			r.enqueueForRobot(context.Background(), "default", q, c.labels...)
+			if got := q.Len(); got != c.want {
+				t.Errorf("expected %d rollouts to be enqueued, got %d", c.want, got)
+			}
+		})
+	}
+}
+
+// BenchmarkReconcile measures a reconcile of a rollout that selects half of
+// a fleet of 5000 robots once all ChartAssignments exist.
+func BenchmarkReconcile(b *testing.B) {
+	ctx := context.Background()
+
+	app := &apps.App{}
+	app.Name = "foo"
+	app.Spec.Components.Robot.Inline = "inline-robot"
+	ar := &apps.AppRollout{}
+	ar.Namespace = "default"
+	ar.Name = "foo"
+	ar.Spec.AppName = "foo"
+	ar.Spec.Robots = []apps.AppRolloutSpecRobot{{
+		Selector: &apps.RobotSelector{LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"fleet": "a"}}},
+	}}
+	r := &Reconciler{kube: newFakeClient(append(newFleet(5000), app, ar)...)}
+
+	reconcileOnce := func() {
+		var cur apps.AppRollout
+		if err := r.kube.Get(ctx, kclient.ObjectKeyFromObject(ar), &cur); err != nil {
+			b.Fatal(err)
+		}
+		if _, err := r.reconcile(ctx, &cur); err != nil {
+			b.Fatal(err)
+		}
+	}
+	// Create the ChartAssignments before measuring.
+	reconcileOnce()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		reconcileOnce()
+	}
+}
@@ -0,0 +1,125 @@
+// Copyright 2026 The Cloud Robotics Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package approllout
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	apps "github.com/SAP/cloud-robotics/src/go/pkg/apis/apps/v1alpha1"
+	core "k8s.io/api/core/v1"
+)
+
+const (
+	// maxRobotMessageLength bounds error messages of robots in the status.
+	maxRobotMessageLength = 200
+	// maxFailedRobotsInCondition is the number of failed robots named in the
+	// RobotsFailed condition.
+	maxFailedRobotsInCondition = 5
+)
+
+// robotStatusRank orders robots in the status by urgency.
+func robotStatusRank(phase apps.ChartAssignmentPhase) int {
+	switch phase {
+	case apps.ChartAssignmentPhaseFailed:
+		return 0
+	case apps.ChartAssignmentPhaseReady:
+		return 2
+	}
+	return 1
+}
+
+// shortMessage truncates msg to maxRobotMessageLength bytes without
+// splitting a multi-byte character.
+func shortMessage(msg string) string {
+	if len(msg) <= maxRobotMessageLength {
+		return msg
+	}
+	n := maxRobotMessageLength - 3
+	for n > 0 && !utf8.RuneStart(msg[n]) {
+		n--
+	}
+	return msg[:n] + "..."
+}
+
+// newRobotStatus summarizes the robot ChartAssignment.
+func newRobotStatus(ca *apps.ChartAssignment) apps.AppRolloutRobotStatus {
+	rs := apps.AppRolloutRobotStatus{
+		Name:    ca.Labels[labelRobotName],
+		Phase:   ca.Status.Phase,
+		Version: ca.Spec.Chart.Version,
+	}
+	for _, c := range ca.Status.Conditions {
+		if rs.LastTransitionTime == nil || rs.LastTransitionTime.Before(&c.LastTransitionTime) {
+			t := c.LastTransitionTime
+			rs.LastTransitionTime = &t
+		}
+		if c.Status != core.ConditionTrue && c.Message != "" && rs.Message == "" {
+			rs.Message = shortMessage(c.Message)
+		}
+	}
+	return rs
+}
+
+// setRobotStatus lists the robot ChartAssignments in the status, most
+// urgent first, and names the first failed robots in the RobotsFailed
+// condition.
+func setRobotStatus(ar *apps.AppRollout, curCAs []apps.ChartAssignment) {
+	var robots []apps.AppRolloutRobotStatus
+	for i := range curCAs {
+		if _, ok := curCAs[i].Labels[labelRobotName]; ok {
+			robots = append(robots, newRobotStatus(&curCAs[i]))
+		}
+	}
+	sort.Slice(robots, func(i, j int) bool {
+		ri, rj := robotStatusRank(robots[i].Phase), robotStatusRank(robots[j].Phase)
+		if ri != rj {
+			return ri < rj
+		}
+		return robots[i].Name < robots[j].Name
+	})
+	var failed []string
+	for _, r := range robots {
+		if r.Phase != apps.ChartAssignmentPhaseFailed {
+			break
+		}
+		failed = append(failed, r.Name)
+	}
+	ar.Status.OmittedRobots = 0
+	if len(robots) > maxStatusRobots {
+		ar.Status.OmittedRobots = int64(len(robots) - maxStatusRobots)
+		robots = robots[:maxStatusRobots]
+	}
+	ar.Status.Robots = robots
+
+	if len(failed) == 0 {
+		setCondition(ar, apps.AppRolloutConditionRobotsFailed, core.ConditionFalse, "")
+		return
+	}
+	msg := fmt.Sprintf("%d robots failed: %s", len(failed), strings.Join(firstN(failed, maxFailedRobotsInCondition), ", "))
+	if len(failed) > maxFailedRobotsInCondition {
+		msg += fmt.Sprintf(" and %d more", len(failed)-maxFailedRobotsInCondition)
+	}
+	setCondition(ar, apps.AppRolloutConditionRobotsFailed, core.ConditionTrue, msg)
+}
+
+func firstN(s []string, n int) []string {
+	if len(s) > n {
+		return s[:n]
+	}
+	return s
+}
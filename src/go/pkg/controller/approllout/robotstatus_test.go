@@ -0,0 +1,88 @@
+// Copyright 2026 The Cloud Robotics Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package approllout
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	apps "github.com/SAP/cloud-robotics/src/go/pkg/apis/apps/v1alpha1"
+	core "k8s.io/api/core/v1"
+)
+
+func TestSetRobotStatus(t *testing.T) {
+	var cas []apps.ChartAssignment
+	for i, ca := range newRobotCAs(maxStatusRobots+10, "1") {
+		ca.Status.Phase = apps.ChartAssignmentPhaseReady
+		if i%10 == 9 {
+			ca.Status.Phase = apps.ChartAssignmentPhaseFailed
+			ca.Status.Conditions = []apps.ChartAssignmentCondition{{
+				Type:    apps.ChartAssignmentConditionSettled,
+				Status:  core.ConditionFalse,
+				Message: strings.Repeat("x", 2*maxRobotMessageLength),
+			}}
+		}
+		cas = append(cas, *ca)
+	}
+	// The cloud ChartAssignment isn't listed.
+	var cloud apps.ChartAssignment
+	cloud.Name = "foo-cloud"
+	cas = append(cas, cloud)
+
+	var ar apps.AppRollout
+	setRobotStatus(&ar, cas)
+
+	if got := len(ar.Status.Robots); got != maxStatusRobots {
+		t.Fatalf("expected %d robots in status, got %d", maxStatusRobots, got)
+	}
+	if got := ar.Status.OmittedRobots; got != 10 {
+		t.Errorf("expected 10 omitted robots, got %d", got)
+	}
+	first := ar.Status.Robots[0]
+	if first.Name != "robot09" || first.Phase != apps.ChartAssignmentPhaseFailed || first.Version != "1" {
+		t.Errorf("expected failed robot09 first, got %+v", first)
+	}
+	if got := len(first.Message); got != maxRobotMessageLength {
+		t.Errorf("expected message to be shortened to %d, got %d", maxRobotMessageLength, got)
+	}
+	c := ar.Status.Conditions[0]
+	if c.Type != apps.AppRolloutConditionRobotsFailed || c.Status != core.ConditionTrue {
+		t.Fatalf("expected RobotsFailed condition, got %+v", c)
+	}
+	if want := "6 robots failed: robot09, robot19, robot29, robot39, robot49 and 1 more"; c.Message != want {
+		t.Errorf("unexpected condition message %q, want %q", c.Message, want)
+	}
+
+	// The condition is cleared once no robots fail.
+	for i := range cas {
+		cas[i].Status.Phase = apps.ChartAssignmentPhaseReady
+	}
+	setRobotStatus(&ar, cas)
+	if c := ar.Status.Conditions[0]; c.Status != core.ConditionFalse {
+		t.Errorf("expected RobotsFailed condition to be false, got %+v", c)
+	}
+}
+
+func TestShortMessage_keepsCharactersIntact(t *testing.T) {
+	msg := strings.Repeat("ä", maxRobotMessageLength)
+	got := shortMessage(msg)
+	if len(got) > maxRobotMessageLength {
+		t.Errorf("expected at most %d bytes, got %d", maxRobotMessageLength, len(got))
+	}
+	if !utf8.ValidString(got) || !strings.HasSuffix(got, "...") {
+		t.Errorf("expected valid truncated message, got %q", got)
+	}
+}
@@ -0,0 +1,115 @@
+// Copyright 2026 The Cloud Robotics Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package approllout
+
+import (
+	"sort"
+
+	apps "github.com/SAP/cloud-robotics/src/go/pkg/apis/apps/v1alpha1"
+	registry "github.com/SAP/cloud-robotics/src/go/pkg/apis/registry/v1alpha1"
+	"github.com/pkg/errors"
+)
+
+// maxStatusRobots bounds per-robot lists in the AppRollout status so that
+// the object stays small for large fleets.
+const maxStatusRobots = 50
+
+// robotSelection is a robot along with the spec.robots entry that applies
+// to it.
+type robotSelection struct {
+	robot *registry.Robot
+	entry int
+	// matched are the indices of all entries that select the robot.
+	matched []int
+}
+
+// selectorSpecificity returns the number of label requirements of the
+// selector. Selectors matching any robot have no requirements.
+func selectorSpecificity(sel *apps.RobotSelector) int {
+	if sel == nil || (sel.Any != nil && *sel.Any) || sel.LabelSelector == nil {
+		return 0
+	}
+	return len(sel.MatchLabels) + len(sel.MatchExpressions)
+}
+
+// precedes returns true if entry i of spec.robots takes precedence over
+// entry j. Higher priorities come first, then more specific selectors, then
+// earlier entries.
+func precedes(entries []apps.AppRolloutSpecRobot, i, j int) bool {
+	if pi, pj := entries[i].Priority, entries[j].Priority; pi != pj {
+		return pi > pj
+	}
+	si, sj := selectorSpecificity(entries[i].Selector), selectorSpecificity(entries[j].Selector)
+	if si != sj {
+		return si > sj
+	}
+	return i < j
+}
+
+// selectRobots resolves which spec.robots entry applies to each robot. The
+// result is sorted by robot name.
+func selectRobots(entries []apps.AppRolloutSpecRobot, allRobots []registry.Robot) ([]robotSelection, error) {
+	selected := map[string]*robotSelection{}
+
+	for i := range entries {
+		robots, err := matchingRobots(allRobots, entries[i].Selector)
+		if err != nil {
+			return nil, errors.Wrapf(err, "entry %d", i)
+		}
+		for j := range robots {
+			// Ensure we don't keep a pointer to the most recent loop item.
+			r := &robots[j]
+			s, ok := selected[r.Name]
+			if !ok {
+				selected[r.Name] = &robotSelection{robot: r, entry: i, matched: []int{i}}
+				continue
+			}
+			s.matched = append(s.matched, i)
+			if precedes(entries, i, s.entry) {
+				s.entry = i
+			}
+		}
+	}
+	res := make([]robotSelection, 0, len(selected))
+	for _, s := range selected {
+		res = append(res, *s)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].robot.Name < res[j].robot.Name
+	})
+	return res, nil
+}
+
+// setRobotSelections lists the selected robots in the status along with the
+// entry they resolved to. The list is truncated to maxStatusRobots.
+func setRobotSelections(ar *apps.AppRollout, selections []robotSelection) {
+	ar.Status.RobotSelections = nil
+	ar.Status.OmittedRobotSelections = 0
+	if len(selections) > maxStatusRobots {
+		ar.Status.OmittedRobotSelections = int64(len(selections) - maxStatusRobots)
+		selections = selections[:maxStatusRobots]
+	}
+	for _, s := range selections {
+		matched := make([]int32, 0, len(s.matched))
+		for _, m := range s.matched {
+			matched = append(matched, int32(m))
+		}
+		ar.Status.RobotSelections = append(ar.Status.RobotSelections, apps.AppRolloutRobotSelection{
+			Name:    s.robot.Name,
+			Entry:   int32(s.entry),
+			Matched: matched,
+		})
+	}
+}
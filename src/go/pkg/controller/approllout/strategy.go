@@ -0,0 +1,253 @@
+// Copyright 2026 The Cloud Robotics Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package approllout
+
+import (
+	"fmt"
+	"time"
+
+	apps "github.com/SAP/cloud-robotics/src/go/pkg/apis/apps/v1alpha1"
+	"github.com/SAP/cloud-robotics/src/go/pkg/controller/chartassignment"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// rolloutPlan determines which robot ChartAssignments may be created or
+// updated in a reconciliation.
+type rolloutPlan struct {
+	// all is true if all robot ChartAssignments may be changed.
+	all    bool
+	robots map[string]bool
+	// requeueAfter is set if the rollout should be reconciled again after
+	// a pause between batches.
+	requeueAfter time.Duration
+}
+
+func (p *rolloutPlan) allowed(name string) bool {
+	return p.all || p.robots[name]
+}
+
+// chartAssignmentObserved returns true if the status of the ChartAssignment
+// refers to its current spec. The status of a robot ChartAssignment is synced
+// from the robot, where the generation differs from the one in the cloud, so
+// the spec hash is compared. The generation is only used if the status was
+// set by a controller that doesn't report the spec hash.
+func chartAssignmentObserved(ca *apps.ChartAssignment) bool {
+	if ca.Status.ObservedSpecHash != "" {
+		return ca.Status.ObservedSpecHash == chartassignment.SpecHash(&ca.Spec)
+	}
+	return ca.Status.ObservedGeneration == ca.Generation
+}
+
+// chartAssignmentReady returns true if the ChartAssignment is ready for its
+// current spec.
+func chartAssignmentReady(ca *apps.ChartAssignment) bool {
+	return chartAssignmentObserved(ca) && ca.Status.Phase == apps.ChartAssignmentPhaseReady
+}
+
+// chartAssignmentFailed returns true if the ChartAssignment failed for its
+// current spec.
+func chartAssignmentFailed(ca *apps.ChartAssignment) bool {
+	return chartAssignmentObserved(ca) && ca.Status.Phase == apps.ChartAssignmentPhaseFailed
+}
+
+// planRollout decides which robot ChartAssignments are changed according to
+// the rollout strategy and updates the rollout progress in the status.
+// robotCAs are the wanted robot ChartAssignments sorted by name and curCAs
+// the existing ChartAssignments by name. ChartAssignments in held are not
+// picked for new batches.
+func planRollout(
+	ar *apps.AppRollout,
+	revision string,
+	robotCAs []*apps.ChartAssignment,
+	curCAs map[string]apps.ChartAssignment,
+	held map[string]string,
+	now time.Time,
+) (*rolloutPlan, error) {
+	st := ar.Spec.Strategy
+	if st == nil {
+		ar.Status.Rollout = nil
+		return &rolloutPlan{all: true}, nil
+	}
+	p := ar.Status.Rollout
+	if p == nil || p.Revision != revision {
+		p = &apps.AppRolloutProgress{Revision: revision}
+		ar.Status.Rollout = p
+	}
+	var (
+		pending []string
+		inBatch = map[string]bool{}
+		// Counts for robots of the current batch that still exist.
+		batchTotal, batchReady, batchFailed int
+	)
+	for _, name := range p.BatchRobots {
+		inBatch[name] = true
+	}
+	p.TotalRobots = int64(len(robotCAs))
+	p.UpdatedRobots, p.ReadyRobots, p.FailedRobots = 0, 0, 0
+
+	for _, ca := range robotCAs {
+		prev, ok := curCAs[ca.Name]
+		upToDate := false
+		if ok {
+			changed, err := chartAssignmentChanged(&prev, ca)
+			if err != nil {
+				return nil, errors.Wrap(err, "check ChartAssignment changed")
+			}
+			upToDate = !changed
+		}
+		if inBatch[ca.Name] {
+			batchTotal++
+		}
+		if !upToDate {
+			pending = append(pending, ca.Name)
+			continue
+		}
+		p.UpdatedRobots++
+		if chartAssignmentReady(&prev) {
+			p.ReadyRobots++
+			if inBatch[ca.Name] {
+				batchReady++
+			}
+		} else if chartAssignmentFailed(&prev) {
+			p.FailedRobots++
+			if inBatch[ca.Name] {
+				batchFailed++
+			}
+		}
+	}
+	total := len(robotCAs)
+	plan := &rolloutPlan{robots: map[string]bool{}}
+
+	// Robots of the current batch are always kept up-to-date, even if the
+	// cache didn't observe their update yet.
+	for _, name := range pending {
+		if inBatch[name] {
+			plan.robots[name] = true
+		}
+	}
+	if p.Phase == apps.AppRolloutProgressPhaseAborted {
+		return plan, nil
+	}
+	maxFailed, err := scaledValue(st.MaxFailed, total, false, 0)
+	if err != nil {
+		return nil, errors.Wrap(err, "max failed")
+	}
+	if int(p.FailedRobots) > maxFailed {
+		p.Phase = apps.AppRolloutProgressPhaseAborted
+		p.Message = fmt.Sprintf("%d robots failed, at most %d allowed", p.FailedRobots, maxFailed)
+		return plan, nil
+	}
+	if len(pending) == 0 {
+		p.Phase = apps.AppRolloutProgressPhaseCompleted
+		p.Message = fmt.Sprintf("%d/%d robots ready", p.ReadyRobots, p.TotalRobots)
+		return plan, nil
+	}
+	if st.Paused {
+		p.Phase = apps.AppRolloutProgressPhasePaused
+		p.Message = "rollout is paused"
+		return plan, nil
+	}
+	maxUnavailable, err := scaledValue(st.MaxUnavailable, batchTotal, false, 0)
+	if err != nil {
+		return nil, errors.Wrap(err, "max unavailable")
+	}
+	if unavailable := batchTotal - batchReady - batchFailed; len(plan.robots) > 0 || unavailable > maxUnavailable {
+		p.Phase = apps.AppRolloutProgressPhaseProgressing
+		p.Message = fmt.Sprintf("batch %d: %d/%d robots ready", p.Batch, batchReady, batchTotal)
+		p.BatchReadyTime = nil
+		return plan, nil
+	}
+	// The current batch is ready, possibly wait before starting the next.
+	if p.Batch > 0 && st.PauseSeconds > 0 {
+		if p.BatchReadyTime == nil {
+			p.BatchReadyTime = &metav1.Time{Time: now}
+		}
+		pause := time.Duration(st.PauseSeconds) * time.Second
+		if wait := p.BatchReadyTime.Add(pause).Sub(now); wait > 0 {
+			p.Phase = apps.AppRolloutProgressPhasePaused
+			p.Message = fmt.Sprintf("batch %d ready, waiting %s before next batch", p.Batch, wait.Round(time.Second))
+			plan.requeueAfter = wait
+			return plan, nil
+		}
+	}
+	var candidates []string
+	for _, name := range pending {
+		if _, ok := held[name]; !ok {
+			candidates = append(candidates, name)
+		}
+	}
+	if len(candidates) == 0 {
+		p.Phase = apps.AppRolloutProgressPhasePaused
+		p.Message = fmt.Sprintf("%d robots held back by gates", len(pending))
+		return plan, nil
+	}
+	batchSize, err := scaledValue(st.BatchSize, total, true, 1)
+	if err != nil {
+		return nil, errors.Wrap(err, "batch size")
+	}
+	if batchSize < 1 {
+		batchSize = 1
+	}
+	if batchSize > len(candidates) {
+		batchSize = len(candidates)
+	}
+	p.Batch++
+	p.BatchRobots = append([]string(nil), candidates[:batchSize]...)
+	p.BatchStartTime = &metav1.Time{Time: now}
+	p.BatchReadyTime = nil
+	p.Phase = apps.AppRolloutProgressPhaseProgressing
+	p.Message = fmt.Sprintf("batch %d: 0/%d robots ready", p.Batch, batchSize)
+
+	for _, name := range p.BatchRobots {
+		plan.robots[name] = true
+	}
+	return plan, nil
+}
+
+// scaledValue resolves an absolute or percentage value relative to total.
+// def is returned if v is unset.
+func scaledValue(v *intstr.IntOrString, total int, roundUp bool, def int) (int, error) {
+	if v == nil {
+		return def, nil
+	}
+	return intstr.GetScaledValueFromIntOrPercent(v, total, roundUp)
+}
+
+// validateStrategy checks that all strategy values are well-formed and not
+// negative.
+func validateStrategy(st *apps.AppRolloutStrategy) error {
+	if st == nil {
+		return nil
+	}
+	for name, v := range map[string]*intstr.IntOrString{
+		"batchSize":      st.BatchSize,
+		"maxUnavailable": st.MaxUnavailable,
+		"maxFailed":      st.MaxFailed,
+	} {
+		n, err := scaledValue(v, 100, true, 0)
+		if err != nil {
+			return errors.Wrapf(err, "invalid %s", name)
+		}
+		if n < 0 {
+			return errors.Errorf("%s must not be negative", name)
+		}
+	}
+	if st.PauseSeconds < 0 {
+		return errors.New("pauseSeconds must not be negative")
+	}
+	return nil
+}
@@ -0,0 +1,265 @@
+// Copyright 2026 The Cloud Robotics Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package approllout
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	apps "github.com/SAP/cloud-robotics/src/go/pkg/apis/apps/v1alpha1"
+	"github.com/SAP/cloud-robotics/src/go/pkg/controller/chartassignment"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// newRobotCAs returns n wanted robot ChartAssignments with the given chart
+// version.
+func newRobotCAs(n int, version string) []*apps.ChartAssignment {
+	var cas []*apps.ChartAssignment
+	for i := 0; i < n; i++ {
+		ca := &apps.ChartAssignment{}
+		ca.Name = fmt.Sprintf("foo-robot-robot%02d", i)
+		setLabel(&ca.ObjectMeta, labelRobotName, fmt.Sprintf("robot%02d", i))
+		ca.Spec.Chart.Version = version
+		cas = append(cas, ca)
+	}
+	return cas
+}
+
+// applyPlan updates curCAs like the reconciler would and sets the phase of
+// all changed ChartAssignments.
+func applyPlan(plan *rolloutPlan, want []*apps.ChartAssignment, cur map[string]apps.ChartAssignment, phase apps.ChartAssignmentPhase) {
+	for _, ca := range want {
+		if plan.allowed(ca.Name) {
+			c := *ca.DeepCopy()
+			c.Status.Phase = phase
+			cur[ca.Name] = c
+		}
+	}
+}
+
+func allowedRobots(plan *rolloutPlan) []string {
+	var names []string
+	for n, ok := range plan.robots {
+		if ok {
+			names = append(names, n)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestPlanRollout_noStrategy(t *testing.T) {
+	ar := &apps.AppRollout{}
+	ar.Status.Rollout = &apps.AppRolloutProgress{Revision: "old"}
+
+	plan, err := planRollout(ar, "rev", newRobotCAs(3, "1"), nil, nil, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !plan.all {
+		t.Error("expected all robots to be updated without a strategy")
+	}
+	if ar.Status.Rollout != nil {
+		t.Errorf("expected rollout progress to be cleared, got %+v", ar.Status.Rollout)
+	}
+}
+
+func TestPlanRollout_batches(t *testing.T) {
+	now := time.Now()
+	batchSize := intstr.FromString("40%")
+	ar := &apps.AppRollout{}
+	ar.Spec.Strategy = &apps.AppRolloutStrategy{BatchSize: &batchSize, PauseSeconds: 60}
+
+	want := newRobotCAs(5, "2")
+	cur := map[string]apps.ChartAssignment{}
+	for _, ca := range newRobotCAs(5, "1") {
+		c := *ca
+		c.Status.Phase = apps.ChartAssignmentPhaseReady
+		cur[ca.Name] = c
+	}
+
+	// 40% of 5 robots rounds up to a first batch of two robots.
+	plan, err := planRollout(ar, "rev", want, cur, nil, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, expected := allowedRobots(plan), []string{"foo-robot-robot00", "foo-robot-robot01"}; !reflect.DeepEqual(got, expected) {
+		t.Fatalf("batch 1: got robots %v, want %v", got, expected)
+	}
+	applyPlan(plan, want, cur, apps.ChartAssignmentPhaseSettled)
+
+	// The batch is not ready yet, so no new robots are updated.
+	plan, err = planRollout(ar, "rev", want, cur, nil, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := allowedRobots(plan); len(got) != 0 {
+		t.Fatalf("expected no robots while batch 1 isn't ready, got %v", got)
+	}
+	if got := ar.Status.Rollout.Phase; got != apps.AppRolloutProgressPhaseProgressing {
+		t.Errorf("expected phase Progressing, got %s", got)
+	}
+	applyPlan(&rolloutPlan{robots: map[string]bool{"foo-robot-robot00": true, "foo-robot-robot01": true}}, want, cur, apps.ChartAssignmentPhaseReady)
+
+	// The batch is ready, but we have to pause before the next one.
+	plan, err = planRollout(ar, "rev", want, cur, nil, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := allowedRobots(plan); len(got) != 0 {
+		t.Fatalf("expected no robots during pause, got %v", got)
+	}
+	if plan.requeueAfter != time.Minute {
+		t.Errorf("expected requeue after 1m, got %s", plan.requeueAfter)
+	}
+	if got := ar.Status.Rollout.Phase; got != apps.AppRolloutProgressPhasePaused {
+		t.Errorf("expected phase Paused, got %s", got)
+	}
+
+	// After the pause the next batch is started.
+	plan, err = planRollout(ar, "rev", want, cur, nil, now.Add(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, expected := allowedRobots(plan), []string{"foo-robot-robot02", "foo-robot-robot03"}; !reflect.DeepEqual(got, expected) {
+		t.Fatalf("batch 2: got robots %v, want %v", got, expected)
+	}
+	if got := ar.Status.Rollout.Batch; got != 2 {
+		t.Errorf("expected batch 2, got %d", got)
+	}
+}
+
+func TestPlanRollout_robotGenerationBehindCloud(t *testing.T) {
+	batchSize := intstr.FromInt(1)
+	ar := &apps.AppRollout{}
+	ar.Spec.Strategy = &apps.AppRolloutStrategy{BatchSize: &batchSize}
+
+	want := newRobotCAs(2, "2")
+	cur := map[string]apps.ChartAssignment{}
+	for _, ca := range want {
+		// The robot's copy was re-created, so its generation and thus
+		// the synced status are behind the cloud's generation.
+		c := *ca.DeepCopy()
+		c.Generation = 5
+		c.Status.ObservedGeneration = 1
+		c.Status.ObservedSpecHash = chartassignment.SpecHash(&c.Spec)
+		c.Status.Phase = apps.ChartAssignmentPhaseReady
+		cur[ca.Name] = c
+	}
+	stale := cur["foo-robot-robot01"]
+	stale.Status.ObservedSpecHash = "stale"
+	cur["foo-robot-robot01"] = stale
+
+	if _, err := planRollout(ar, "rev", want, cur, nil, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	if got := ar.Status.Rollout.ReadyRobots; got != 1 {
+		t.Errorf("expected 1 ready robot, got %d", got)
+	}
+}
+
+func TestPlanRollout_abortsOnFailure(t *testing.T) {
+	now := time.Now()
+	ar := &apps.AppRollout{}
+	ar.Spec.Strategy = &apps.AppRolloutStrategy{}
+
+	want := newRobotCAs(3, "2")
+	cur := map[string]apps.ChartAssignment{}
+
+	plan, err := planRollout(ar, "rev", want, cur, nil, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	applyPlan(plan, want, cur, apps.ChartAssignmentPhaseFailed)
+
+	plan, err = planRollout(ar, "rev", want, cur, nil, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := allowedRobots(plan); len(got) != 0 {
+		t.Fatalf("expected no robots after failure, got %v", got)
+	}
+	if got := ar.Status.Rollout.Phase; got != apps.AppRolloutProgressPhaseAborted {
+		t.Fatalf("expected phase Aborted, got %s", got)
+	}
+
+	// A new revision restarts the rollout.
+	plan, err = planRollout(ar, "rev2", newRobotCAs(3, "3"), cur, nil, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, expected := allowedRobots(plan), []string{"foo-robot-robot00"}; !reflect.DeepEqual(got, expected) {
+		t.Fatalf("got robots %v, want %v", got, expected)
+	}
+}
+
+func TestPlanRollout_maxUnavailable(t *testing.T) {
+	now := time.Now()
+	two := intstr.FromInt(2)
+	one := intstr.FromInt(1)
+	ar := &apps.AppRollout{}
+	ar.Spec.Strategy = &apps.AppRolloutStrategy{BatchSize: &two, MaxUnavailable: &one}
+	ar.Status.Rollout = &apps.AppRolloutProgress{
+		Revision:       "rev",
+		Batch:          1,
+		BatchRobots:    []string{"foo-robot-robot00", "foo-robot-robot01"},
+		BatchStartTime: &metav1.Time{Time: now},
+	}
+	want := newRobotCAs(4, "2")
+	cur := map[string]apps.ChartAssignment{}
+	applyPlan(&rolloutPlan{robots: map[string]bool{"foo-robot-robot00": true}}, want, cur, apps.ChartAssignmentPhaseReady)
+	applyPlan(&rolloutPlan{robots: map[string]bool{"foo-robot-robot01": true}}, want, cur, apps.ChartAssignmentPhaseSettled)
+
+	plan, err := planRollout(ar, "rev", want, cur, nil, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, expected := allowedRobots(plan), []string{"foo-robot-robot02", "foo-robot-robot03"}; !reflect.DeepEqual(got, expected) {
+		t.Fatalf("got robots %v, want %v", got, expected)
+	}
+}
+
+func TestValidateStrategy(t *testing.T) {
+	neg := intstr.FromInt(-1)
+	bad := intstr.FromString("ten")
+	ok := intstr.FromString("10%")
+
+	cases := []struct {
+		name       string
+		st         *apps.AppRolloutStrategy
+		shouldFail bool
+	}{
+		{name: "unset"},
+		{name: "percentage", st: &apps.AppRolloutStrategy{BatchSize: &ok, MaxFailed: &ok}},
+		{name: "negative", st: &apps.AppRolloutStrategy{MaxUnavailable: &neg}, shouldFail: true},
+		{name: "malformed", st: &apps.AppRolloutStrategy{BatchSize: &bad}, shouldFail: true},
+		{name: "negative-pause", st: &apps.AppRolloutStrategy{PauseSeconds: -5}, shouldFail: true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := validateStrategy(c.st)
+			if err == nil && c.shouldFail {
+				t.Fatal("expected failure but got none")
+			}
+			if err != nil && !c.shouldFail {
+				t.Fatalf("unexpected error: %s", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+// Copyright 2026 The Cloud Robotics Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package approllout
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"text/template"
+
+	"github.com/Masterminds/sprig"
+	apps "github.com/SAP/cloud-robotics/src/go/pkg/apis/apps/v1alpha1"
+	registry "github.com/SAP/cloud-robotics/src/go/pkg/apis/registry/v1alpha1"
+	"github.com/pkg/errors"
+	"k8s.io/helm/pkg/chartutil"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+type errValuesTemplate struct {
+	robot string
+	err   error
+}
+
+func (e errValuesTemplate) Error() string {
+	if e.robot == "" {
+		return fmt.Sprintf("parse values template: %s", e.err)
+	}
+	return fmt.Sprintf("render values template for robot %q: %s", e.robot, e.err)
+}
+
+// robotTemplateData is passed to values templates.
+type robotTemplateData struct {
+	Robot robotTemplateValues
+}
+
+type robotTemplateValues struct {
+	Name        string
+	Type        string
+	Labels      map[string]string
+	Annotations map[string]string
+}
+
+// nonRepeatableFuncs are sprig functions that are removed from
+// sprig.HermeticTxtFuncMap() in addition. env and expandenv would expose the
+// controller's environment, like in Helm, and the others return different
+// results on every call, so that the ChartAssignments would be updated on
+// every reconcile.
+var nonRepeatableFuncs = []string{
+	"env", "expandenv", "ago", "shuffle",
+	"genPrivateKey", "genCA", "genSelfSignedCert", "genSignedCert",
+}
+
+// valuesTemplateFuncs returns the functions available in values templates:
+// the sprig functions that Helm charts can use, except for those that don't
+// render repeatably.
+func valuesTemplateFuncs() template.FuncMap {
+	funcs := sprig.HermeticTxtFuncMap()
+	for _, name := range nonRepeatableFuncs {
+		delete(funcs, name)
+	}
+	return funcs
+}
+
+// parseValuesTemplate parses a values template.
+func parseValuesTemplate(text string) (*template.Template, error) {
+	return template.New("valuesTemplate").
+		Funcs(valuesTemplateFuncs()).
+		Option("missingkey=zero").
+		Parse(text)
+}
+
+// parseValuesTemplates parses the values templates of the robot selectors.
+// The result is indexed like specs and nil for selectors without template.
+func parseValuesTemplates(specs []apps.AppRolloutSpecRobot) ([]*template.Template, error) {
+	res := make([]*template.Template, len(specs))
+	for i, spec := range specs {
+		if spec.ValuesTemplate == "" {
+			continue
+		}
+		tmpl, err := parseValuesTemplate(spec.ValuesTemplate)
+		if err != nil {
+			return nil, errValuesTemplate{err: err}
+		}
+		res[i] = tmpl
+	}
+	return res, nil
+}
+
+// renderValuesTemplate renders the values template for the robot and parses
+// the result as YAML values.
+func renderValuesTemplate(tmpl *template.Template, robot *registry.Robot) (chartutil.Values, error) {
+	data := robotTemplateData{
+		Robot: robotTemplateValues{
+			Name:        robot.Name,
+			Type:        robot.Spec.Type,
+			Labels:      robot.Labels,
+			Annotations: robot.Annotations,
+		},
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, err
+	}
+	vals, err := chartutil.ReadValues(buf.Bytes())
+	if err != nil {
+		return nil, errors.Wrap(err, "parse rendered values")
+	}
+	return vals, nil
+}
+
+// robotTemplateInputChanged returns true if an update of a robot may change
+// the result of values templates.
+func robotTemplateInputChanged(old, cur kclient.Object) bool {
+	if !reflect.DeepEqual(old.GetAnnotations(), cur.GetAnnotations()) {
+		return true
+	}
+	oldRobot, ok1 := old.(*registry.Robot)
+	curRobot, ok2 := cur.(*registry.Robot)
+	return ok1 && ok2 && oldRobot.Spec.Type != curRobot.Spec.Type
+}
@@ -16,14 +16,18 @@ package chartassignment
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"hash/fnv"
 	"log"
 	"net/http"
+	"reflect"
 	"strings"
 	"time"
 
 	apps "github.com/SAP/cloud-robotics/src/go/pkg/apis/apps/v1alpha1"
 	"github.com/SAP/cloud-robotics/src/go/pkg/coretools"
+	"github.com/SAP/cloud-robotics/src/go/pkg/synk"
 	"github.com/pkg/errors"
 	core "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
@@ -54,7 +58,9 @@ const (
 // Add adds a controller and validation webhook for the ChartAssignment resource type
 // to the manager and server.
 // Handled ChartAssignments are filtered by the provided cluster.
-func Add(ctx context.Context, mgr manager.Manager, cluster string, copyPullSecret bool) error {
+// If debugMux is not nil, the in-memory release state is served on it at
+// /debug/releases.
+func Add(ctx context.Context, mgr manager.Manager, cluster string, copyPullSecret bool, debugMux *http.ServeMux) error {
 	r := &Reconciler{
 		kube:           mgr.GetClient(),
 		recorder:       mgr.GetEventRecorderFor("chartassignment-controller"),
@@ -66,6 +72,9 @@ func Add(ctx context.Context, mgr manager.Manager, cluster string, copyPullSecre
 	if err != nil {
 		return err
 	}
+	if debugMux != nil {
+		debugMux.Handle("/debug/releases", r.releases)
+	}
 
 	c, err := controller.New("chartassignment", mgr, controller.Options{
 		Reconciler: r,
@@ -180,6 +189,7 @@ func (r *Reconciler) ensureNamespace(ctx context.Context, as *apps.ChartAssignme
 	createNamespace := k8serrors.IsNotFound(err)
 	ns.Name = as.Spec.NamespaceName
 	setLabel(&ns.ObjectMeta, "app", as.Name)
+	setNamespaceAppLabels(&ns, as)
 
 	if createNamespace {
 		return &ns, r.kube.Create(ctx, &ns)
@@ -276,6 +286,12 @@ func (r *Reconciler) reconcile(ctx context.Context, as *apps.ChartAssignment) (r
 			return reconcile.Result{}, fmt.Errorf("ensure service-account: %s", err)
 		}
 	}
+	if err := r.ensureResourceLimits(ctx, as); err != nil {
+		return reconcile.Result{}, fmt.Errorf("ensure resource limits: %s", err)
+	}
+	if err := r.ensureNetworkPolicies(ctx, as); err != nil {
+		return reconcile.Result{}, fmt.Errorf("ensure network policies: %s", err)
+	}
 	// Ensure a finalizer on the ChartAssignment so we don't get deleted before
 	// we've properly deleted the associated Synk ResourceSet.
 	if !stringsContain(as.Finalizers, finalizer) {
@@ -312,6 +328,18 @@ func condition(b bool) core.ConditionStatus {
 	return core.ConditionFalse
 }
 
+// SpecHash returns a hash of the ChartAssignment spec. It's reported in the
+// status to tell which spec the status refers to.
+func SpecHash(spec *apps.ChartAssignmentSpec) string {
+	b, err := json.Marshal(spec)
+	if err != nil {
+		return ""
+	}
+	h := fnv.New64a()
+	h.Write(b)
+	return fmt.Sprintf("%016x", h.Sum64())
+}
+
 func (r *Reconciler) setStatus(ctx context.Context, as *apps.ChartAssignment) error {
 	status, ok := r.releases.status(as)
 	if !ok {
@@ -322,7 +350,9 @@ func (r *Reconciler) setStatus(ctx context.Context, as *apps.ChartAssignment) er
 		return nil
 	}
 
+	prevPhase := as.Status.Phase
 	as.Status.ObservedGeneration = as.Generation
+	as.Status.ObservedSpecHash = SpecHash(&as.Spec)
 	as.Status.Phase = status.phase
 
 	if c := condition(status.phase == apps.ChartAssignmentPhaseSettled); status.err == nil {
@@ -366,8 +396,36 @@ func (r *Reconciler) setStatus(ctx context.Context, as *apps.ChartAssignment) er
 			setCondition(as, apps.ChartAssignmentConditionReady, condition(ready == total),
 				fmt.Sprintf("%d/%d pods are running or succeeded", ready, total))
 		}
+		quota, err := r.quotaStatus(ctx, as)
+		if err != nil {
+			return errors.Wrap(err, "get resource quota")
+		}
+		as.Status.Quota = quota
+	}
+	if err := r.kube.Status().Update(ctx, as); err != nil {
+		return err
+	}
+	r.recordTransition(as, prevPhase, status)
+	return nil
+}
+
+// recordTransition updates metrics and emits an event if the phase of the
+// ChartAssignment changed from prev.
+func (r *Reconciler) recordTransition(as *apps.ChartAssignment, prev apps.ChartAssignmentPhase, status releaseStatus) {
+	cur := as.Status.Phase
+	if cur == prev {
+		return
+	}
+	phaseTransitions.WithLabelValues(string(prev), string(cur)).Inc()
+
+	if cur == apps.ChartAssignmentPhaseReady {
+		if !status.accepted.IsZero() {
+			timeToReady.Observe(time.Since(status.accepted).Seconds())
+		}
+		r.recorder.Event(as, core.EventTypeNormal, "Ready", "all pods are running or succeeded")
+	} else if prev == apps.ChartAssignmentPhaseReady {
+		r.recorder.Eventf(as, core.EventTypeWarning, "NotReady", "phase changed to %s", cur)
 	}
-	return r.kube.Status().Update(ctx, as)
 }
 
 // ensureDeleted ensures that the Synk ResourceSet is deleted and the finalizer gets removed.
@@ -475,22 +533,30 @@ func setLabel(o *meta.ObjectMeta, k, v string) {
 }
 
 // NewValidationWebhook returns a new webhook that validates ChartAssignments.
-func NewValidationWebhook(mgr manager.Manager) *admission.Webhook {
-	return &admission.Webhook{Handler: newChartAssignmentValidator(mgr.GetScheme())}
+// If renderCharts is true, the chart of a new or changed ChartAssignment is
+// loaded and rendered with its values, and the ChartAssignment is rejected
+// if rendering fails.
+func NewValidationWebhook(mgr manager.Manager, renderCharts bool) *admission.Webhook {
+	v := newChartAssignmentValidator(mgr.GetScheme())
+	v.renderCharts = renderCharts
+	return &admission.Webhook{Handler: v}
 }
 
 // NewValidationWebhookForEdgeCluster returns a webhook that checks
 // ChartAssignments are valid and apply to a cluster with the given name.
-func NewValidationWebhookForEdgeCluster(mgr manager.Manager, clusterName string) *admission.Webhook {
+// See NewValidationWebhook for renderCharts.
+func NewValidationWebhookForEdgeCluster(mgr manager.Manager, clusterName string, renderCharts bool) *admission.Webhook {
 	v := newChartAssignmentValidator(mgr.GetScheme())
 	v.clusterName = clusterName
+	v.renderCharts = renderCharts
 	return &admission.Webhook{Handler: v}
 }
 
 // chartAssignmentValidator implements a validation webhook.
 type chartAssignmentValidator struct {
-	decoder     runtime.Decoder
-	clusterName string
+	decoder      runtime.Decoder
+	clusterName  string
+	renderCharts bool
 }
 
 func newChartAssignmentValidator(sc *runtime.Scheme) *chartAssignmentValidator {
@@ -516,9 +582,48 @@ func (v *chartAssignmentValidator) Handle(_ context.Context, req admission.Reque
 	if err := v.validate(cur, old); err != nil {
 		return admission.Denied(err.Error())
 	}
+	if v.renderCharts && chartChanged(cur, old) {
+		retry, err := validateChart(cur)
+		if err != nil && retry {
+			// Don't block admission if the chart repository is temporarily
+			// unavailable. The controller will retry loading the chart.
+			return admission.Allowed("").WithWarnings(fmt.Sprintf("chart not validated: %s", err))
+		} else if err != nil {
+			return admission.Denied(err.Error())
+		}
+	}
 	return admission.Allowed("")
 }
 
+// chartChanged returns true if the ChartAssignment is new or its chart
+// reference or values changed. Other updates, e.g. of finalizers, don't
+// require the chart to be rendered again.
+func chartChanged(cur, old *apps.ChartAssignment) bool {
+	if cur.DeletionTimestamp != nil {
+		return false
+	}
+	return old == nil || !reflect.DeepEqual(cur.Spec.Chart, old.Spec.Chart)
+}
+
+// validateChart loads and renders the chart of the ChartAssignment the same
+// way the controller does and checks that the resulting resources can be
+// applied to the app namespace. retry is true if the chart could not be
+// fetched from its repository, which may be a transient error.
+func validateChart(as *apps.ChartAssignment) (retry bool, err error) {
+	c, values, err := loadChart(&as.Spec.Chart)
+	if err != nil {
+		return isFetchError(err), err
+	}
+	if err := validateValues(c, values); err != nil {
+		return false, err
+	}
+	resources, err := expandChart(as, c, values)
+	if err != nil {
+		return false, err
+	}
+	return false, synk.ValidateNamespaces(as.Spec.NamespaceName, resources...)
+}
+
 func (v *chartAssignmentValidator) validate(cur, old *apps.ChartAssignment) error {
 	if cur.Spec.ClusterName == "" {
 		return fmt.Errorf("cluster name missing")
@@ -553,5 +658,11 @@ func (v *chartAssignmentValidator) validate(cur, old *apps.ChartAssignment) erro
 	} else if c.Repository == "" || c.Name == "" || c.Version == "" {
 		return fmt.Errorf("non-inline chart must be fully specified")
 	}
+	if err := validateResources(cur.Spec.Resources); err != nil {
+		return fmt.Errorf("invalid resources: %s", err)
+	}
+	if err := validateNetworkIsolation(cur.Spec.NetworkIsolation); err != nil {
+		return fmt.Errorf("invalid network isolation: %s", err)
+	}
 	return nil
 }
@@ -19,6 +19,7 @@ import (
 	"testing"
 
 	apps "github.com/SAP/cloud-robotics/src/go/pkg/apis/apps/v1alpha1"
+	"github.com/SAP/cloud-robotics/src/go/pkg/kubetest"
 	"sigs.k8s.io/yaml"
 )
 
@@ -129,6 +130,40 @@ spec:
     repository: https://some.repo
     name: chartname
     version: 1.3.4
+	`,
+			shouldFail: true,
+		},
+		{
+			name: "valid-resources",
+			cur: `
+spec:
+  clusterName: c1
+  namespaceName: ns1
+  chart:
+    inline: abc
+  resources:
+    quota:
+      limits.cpu: 2
+      pods: 10
+    defaultLimits:
+      memory: 512Mi
+    defaultRequests:
+      memory: 128Mi
+	`,
+		},
+		{
+			name: "default-request-exceeds-limit",
+			cur: `
+spec:
+  clusterName: c1
+  namespaceName: ns1
+  chart:
+    inline: abc
+  resources:
+    defaultLimits:
+      cpu: 500m
+    defaultRequests:
+      cpu: 1
 	`,
 			shouldFail: true,
 		},
@@ -238,3 +273,78 @@ spec:
 		})
 	}
 }
+
+func TestValidateChart(t *testing.T) {
+	cases := []struct {
+		name       string
+		template   string
+		values     string
+		shouldFail bool
+	}{
+		{
+			name: "valid",
+			template: `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: foo
+data:
+  foo: {{ .Values.foo | quote }}`,
+			values: `foo: bar`,
+		},
+		{
+			name: "valid-kube-system",
+			template: `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: foo
+  namespace: kube-system`,
+		},
+		{
+			name:       "template-fails",
+			template:   `{{ required "foo is required" .Values.foo }}`,
+			shouldFail: true,
+		},
+		{
+			name:       "invalid-manifest",
+			template:   `foo: [`,
+			shouldFail: true,
+		},
+		{
+			name: "other-namespace",
+			template: `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: foo
+  namespace: other`,
+			shouldFail: true,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			as := &apps.ChartAssignment{}
+			unmarshalYAML(t, as, `
+metadata:
+  name: test-assignment-1
+  namespace: default
+spec:
+  clusterName: c1
+  namespaceName: ns1
+	`)
+			as.Spec.Chart.Inline = kubetest.BuildInlineChart(t, ChartName, c.template, c.values)
+
+			retry, err := validateChart(as)
+			if retry {
+				t.Errorf("unexpected retry for inline chart")
+			}
+			if err == nil && c.shouldFail {
+				t.Fatal("expected failure but got none")
+			}
+			if err != nil && !c.shouldFail {
+				t.Fatalf("unexpected error: %s", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+// Copyright 2026 The Cloud Robotics Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chartassignment
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Steps of a release update or deletion for which durations and failures
+// are reported.
+const (
+	stepFetch    = "fetch"
+	stepValidate = "validate"
+	stepRender   = "render"
+	stepApply    = "apply"
+	stepDelete   = "delete"
+)
+
+var (
+	phaseTransitions = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "chartassignment_phase_transitions_total",
+			Help: "Number of ChartAssignment phase transitions",
+		},
+		[]string{"from", "to"},
+	)
+	timeToReady = prometheus.NewHistogram(
+		prometheus.HistogramOpts{
+			Name:    "chartassignment_time_to_ready_seconds",
+			Help:    "Time from accepting a ChartAssignment generation until it becomes ready",
+			Buckets: prometheus.ExponentialBuckets(1, 2, 12),
+		},
+	)
+	stepDurations = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "chartassignment_release_step_duration_seconds",
+			Help:    "Duration of fetching, rendering, applying and deleting releases",
+			Buckets: prometheus.ExponentialBuckets(0.01, 2, 14),
+		},
+		[]string{"step"},
+	)
+	releaseFailures = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "chartassignment_release_failures_total",
+			Help: "Number of failed release steps by step and whether they are retried",
+		},
+		[]string{"step", "retry"},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(phaseTransitions)
+	prometheus.MustRegister(timeToReady)
+	prometheus.MustRegister(stepDurations)
+	prometheus.MustRegister(releaseFailures)
+}
+
+// stepError annotates an error with the release step it occurred in.
+type stepError struct {
+	step string
+	err  error
+}
+
+func (e *stepError) Error() string { return e.err.Error() }
+func (e *stepError) Cause() error  { return e.err }
+
+// failedStep returns the step an error was annotated with, or "unknown".
+func failedStep(err error) string {
+	for err != nil {
+		if se, ok := err.(*stepError); ok {
+			return se.step
+		}
+		c, ok := err.(interface{ Cause() error })
+		if !ok {
+			break
+		}
+		err = c.Cause()
+	}
+	return "unknown"
+}
+
+// observeStep records the duration of a release step that began at start.
+func observeStep(step string, start time.Time) {
+	stepDurations.WithLabelValues(step).Observe(time.Since(start).Seconds())
+}
+
+func recordFailure(step string, retry bool) {
+	releaseFailures.WithLabelValues(step, strconv.FormatBool(retry)).Inc()
+}
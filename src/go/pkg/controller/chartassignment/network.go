@@ -0,0 +1,214 @@
+// Copyright 2026 The Cloud Robotics Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chartassignment
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	apps "github.com/SAP/cloud-robotics/src/go/pkg/apis/apps/v1alpha1"
+	core "k8s.io/api/core/v1"
+	networking "k8s.io/api/networking/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// LabelAppName is set on ChartAssignments generated for an app. The
+	// controller copies it to the app namespace so that NetworkPolicies of
+	// other apps can select it.
+	LabelAppName = "cloudrobotics.com/app-name"
+	// labelDependencyPrefix is prepended to the names of the dependencies of
+	// an app to label its namespace. Isolated namespaces admit traffic from
+	// namespaces carrying the label for their own app.
+	labelDependencyPrefix = "dependency.cloudrobotics.com/"
+
+	networkPolicyDefaultDeny = "chartassignment-default-deny"
+	networkPolicyAllow       = "chartassignment-allow"
+
+	// Port the metadata-server listens on on robot clusters, see
+	// charts/base-robot/templates/metadata-server.yaml.
+	metadataServerPort = 8965
+)
+
+// appName returns the name of the app a ChartAssignment belongs to. Manually
+// created ChartAssignments are treated as an app of their own.
+func appName(as *apps.ChartAssignment) string {
+	if n := as.Labels[LabelAppName]; n != "" {
+		return n
+	}
+	return as.Name
+}
+
+func networkIsolated(as *apps.ChartAssignment) bool {
+	ni := as.Spec.NetworkIsolation
+	return ni != nil && ni.Mode == apps.NetworkIsolationNamespace
+}
+
+// setNamespaceAppLabels labels the namespace with the app name and the
+// app's dependencies. Stale dependency labels are removed.
+func setNamespaceAppLabels(ns *core.Namespace, as *apps.ChartAssignment) {
+	setLabel(&ns.ObjectMeta, LabelAppName, appName(as))
+
+	for k := range ns.Labels {
+		if strings.HasPrefix(k, labelDependencyPrefix) {
+			delete(ns.Labels, k)
+		}
+	}
+	if as.Spec.NetworkIsolation == nil {
+		return
+	}
+	for _, dep := range as.Spec.NetworkIsolation.Dependencies {
+		setLabel(&ns.ObjectMeta, labelDependencyPrefix+dep, "true")
+	}
+}
+
+// ensureNetworkPolicies creates, updates, or deletes the NetworkPolicies in
+// the app namespace according to spec.networkIsolation.
+func (r *Reconciler) ensureNetworkPolicies(ctx context.Context, as *apps.ChartAssignment) error {
+	var want []*networking.NetworkPolicy
+	if networkIsolated(as) {
+		want = newNetworkPolicies(as)
+	}
+	wantByName := map[string]*networking.NetworkPolicy{}
+	for _, np := range want {
+		wantByName[np.Name] = np
+	}
+	for _, name := range []string{networkPolicyDefaultDeny, networkPolicyAllow} {
+		var cur networking.NetworkPolicy
+		err := r.kube.Get(ctx, kclient.ObjectKey{Namespace: as.Spec.NamespaceName, Name: name}, &cur)
+		if err != nil && !k8serrors.IsNotFound(err) {
+			return fmt.Errorf("getting NetworkPolicy \"%s:%s\" failed: %s", as.Spec.NamespaceName, name, err)
+		}
+		exists := !k8serrors.IsNotFound(err)
+
+		np, ok := wantByName[name]
+		if !ok {
+			if !exists {
+				continue
+			}
+			if err := r.kube.Delete(ctx, &cur); err != nil && !k8serrors.IsNotFound(err) {
+				return fmt.Errorf("deleting NetworkPolicy \"%s:%s\" failed: %s", as.Spec.NamespaceName, name, err)
+			}
+			continue
+		}
+		if !exists {
+			if err := r.kube.Create(ctx, np); err != nil {
+				return fmt.Errorf("creating NetworkPolicy \"%s:%s\" failed: %s", as.Spec.NamespaceName, name, err)
+			}
+			continue
+		}
+		cur.Labels = np.Labels
+		cur.Spec = np.Spec
+		if err := r.kube.Update(ctx, &cur); err != nil {
+			return fmt.Errorf("updating NetworkPolicy \"%s:%s\" failed: %s", as.Spec.NamespaceName, name, err)
+		}
+	}
+	return nil
+}
+
+// newNetworkPolicies returns a policy denying all ingress and egress traffic
+// in the namespace and a policy allowing traffic within the namespace, to DNS,
+// to the metadata-server, to the namespaces of the app's dependencies, and
+// from the namespaces of apps that depend on this app.
+func newNetworkPolicies(as *apps.ChartAssignment) []*networking.NetworkPolicy {
+	var (
+		udp       = core.ProtocolUDP
+		tcp       = core.ProtocolTCP
+		dnsPort   = intstr.FromInt(53)
+		mdsPort   = intstr.FromInt(metadataServerPort)
+		sameNs    = networking.NetworkPolicyPeer{PodSelector: &meta.LabelSelector{}}
+		dependent = networking.NetworkPolicyPeer{
+			NamespaceSelector: &meta.LabelSelector{
+				MatchLabels: map[string]string{labelDependencyPrefix + appName(as): "true"},
+			},
+		}
+		policyTypes = []networking.PolicyType{networking.PolicyTypeIngress, networking.PolicyTypeEgress}
+	)
+	deny := &networking.NetworkPolicy{}
+	deny.Namespace = as.Spec.NamespaceName
+	deny.Name = networkPolicyDefaultDeny
+	setLabel(&deny.ObjectMeta, "app", as.Name)
+	deny.Spec.PolicyTypes = policyTypes
+
+	allow := &networking.NetworkPolicy{}
+	allow.Namespace = as.Spec.NamespaceName
+	allow.Name = networkPolicyAllow
+	setLabel(&allow.ObjectMeta, "app", as.Name)
+	allow.Spec.PolicyTypes = policyTypes
+	allow.Spec.Ingress = []networking.NetworkPolicyIngressRule{
+		{From: []networking.NetworkPolicyPeer{sameNs, dependent}},
+	}
+	allow.Spec.Egress = []networking.NetworkPolicyEgressRule{
+		{To: []networking.NetworkPolicyPeer{sameNs}},
+		{
+			// DNS may be served from kube-system or the node, so the
+			// destination is not restricted.
+			Ports: []networking.NetworkPolicyPort{
+				{Protocol: &udp, Port: &dnsPort},
+				{Protocol: &tcp, Port: &dnsPort},
+			},
+		},
+		{
+			// The metadata-server runs with hostNetwork and DNATs
+			// 169.254.169.254:80 to 127.0.0.1:8965 on the node in
+			// nat PREROUTING. Policies are enforced after that, so
+			// they see the node as destination rather than
+			// 169.254.169.254, and the address of the node differs
+			// between CNIs. Only the port is restricted therefore.
+			Ports: []networking.NetworkPolicyPort{{Protocol: &tcp, Port: &mdsPort}},
+		},
+	}
+	if deps := as.Spec.NetworkIsolation.Dependencies; len(deps) > 0 {
+		allow.Spec.Egress = append(allow.Spec.Egress, networking.NetworkPolicyEgressRule{
+			To: []networking.NetworkPolicyPeer{{
+				NamespaceSelector: &meta.LabelSelector{
+					MatchExpressions: []meta.LabelSelectorRequirement{{
+						Key:      LabelAppName,
+						Operator: meta.LabelSelectorOpIn,
+						Values:   deps,
+					}},
+				},
+			}},
+		})
+	}
+	return []*networking.NetworkPolicy{deny, allow}
+}
+
+// validateNetworkIsolation checks the isolation mode and that dependencies
+// are valid app names.
+func validateNetworkIsolation(ni *apps.NetworkIsolation) error {
+	if ni == nil {
+		return nil
+	}
+	switch ni.Mode {
+	case "", apps.NetworkIsolationNone, apps.NetworkIsolationNamespace:
+	default:
+		return fmt.Errorf("unknown mode %q", ni.Mode)
+	}
+	for _, dep := range ni.Dependencies {
+		if errs := validation.IsValidLabelValue(dep); len(errs) > 0 {
+			return fmt.Errorf("invalid dependency %q: %s", dep, strings.Join(errs, ", "))
+		}
+		if errs := validation.IsQualifiedName(labelDependencyPrefix + dep); len(errs) > 0 {
+			return fmt.Errorf("invalid dependency %q: %s", dep, strings.Join(errs, ", "))
+		}
+	}
+	return nil
+}
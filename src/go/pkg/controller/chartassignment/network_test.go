@@ -0,0 +1,143 @@
+// Copyright 2026 The Cloud Robotics Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chartassignment
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	apps "github.com/SAP/cloud-robotics/src/go/pkg/apis/apps/v1alpha1"
+	core "k8s.io/api/core/v1"
+	networking "k8s.io/api/networking/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestSetNamespaceAppLabels(t *testing.T) {
+	var as apps.ChartAssignment
+	unmarshalYAML(t, &as, `
+metadata:
+  name: foo-robot-robot1
+  labels:
+    cloudrobotics.com/app-name: foo
+spec:
+  networkIsolation:
+    mode: Namespace
+    dependencies: [bar]
+	`)
+	var ns core.Namespace
+	ns.Labels = map[string]string{
+		"dependency.cloudrobotics.com/stale": "true",
+		"other":                              "label",
+	}
+	setNamespaceAppLabels(&ns, &as)
+
+	want := map[string]string{
+		"cloudrobotics.com/app-name":       "foo",
+		"dependency.cloudrobotics.com/bar": "true",
+		"other":                            "label",
+	}
+	if !reflect.DeepEqual(ns.Labels, want) {
+		t.Errorf("unexpected labels: got %v, want %v", ns.Labels, want)
+	}
+}
+
+func TestEnsureNetworkPolicies(t *testing.T) {
+	ctx := context.Background()
+	sc := runtime.NewScheme()
+	scheme.AddToScheme(sc)
+	apps.AddToScheme(sc)
+	r := &Reconciler{kube: fake.NewClientBuilder().WithScheme(sc).Build()}
+
+	var as apps.ChartAssignment
+	unmarshalYAML(t, &as, `
+metadata:
+  name: foo-cloud
+  namespace: default
+  labels:
+    cloudrobotics.com/app-name: foo
+spec:
+  clusterName: cloud
+  namespaceName: app-foo
+  networkIsolation:
+    mode: Namespace
+    dependencies: [bar]
+	`)
+	if err := r.ensureNetworkPolicies(ctx, &as); err != nil {
+		t.Fatal(err)
+	}
+	var deny networking.NetworkPolicy
+	if err := r.kube.Get(ctx, kclient.ObjectKey{Namespace: "app-foo", Name: networkPolicyDefaultDeny}, &deny); err != nil {
+		t.Fatalf("get default-deny policy: %s", err)
+	}
+	if len(deny.Spec.Ingress) != 0 || len(deny.Spec.Egress) != 0 || len(deny.Spec.PolicyTypes) != 2 {
+		t.Errorf("default-deny policy must deny all ingress and egress, got %+v", deny.Spec)
+	}
+	var allow networking.NetworkPolicy
+	if err := r.kube.Get(ctx, kclient.ObjectKey{Namespace: "app-foo", Name: networkPolicyAllow}, &allow); err != nil {
+		t.Fatalf("get allow policy: %s", err)
+	}
+	if got := allow.Spec.Ingress[0].From[1].NamespaceSelector.MatchLabels; !reflect.DeepEqual(got, map[string]string{"dependency.cloudrobotics.com/foo": "true"}) {
+		t.Errorf("unexpected ingress selector for dependent apps: %v", got)
+	}
+	mdsRule := allow.Spec.Egress[2]
+	if len(mdsRule.To) != 0 || mdsRule.Ports[0].Port.IntValue() != metadataServerPort {
+		t.Errorf("expected egress to the metadata-server port on the node, got %+v", mdsRule)
+	}
+	depRule := allow.Spec.Egress[len(allow.Spec.Egress)-1]
+	if got := depRule.To[0].NamespaceSelector.MatchExpressions[0].Values; !reflect.DeepEqual(got, []string{"bar"}) {
+		t.Errorf("unexpected egress selector for dependencies: %v", got)
+	}
+
+	// Disabling isolation removes the policies.
+	as.Spec.NetworkIsolation.Mode = apps.NetworkIsolationNone
+	if err := r.ensureNetworkPolicies(ctx, &as); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{networkPolicyDefaultDeny, networkPolicyAllow} {
+		var np networking.NetworkPolicy
+		if err := r.kube.Get(ctx, kclient.ObjectKey{Namespace: "app-foo", Name: name}, &np); !k8serrors.IsNotFound(err) {
+			t.Errorf("expected NetworkPolicy %q to be deleted, got err %v", name, err)
+		}
+	}
+}
+
+func TestValidateNetworkIsolation(t *testing.T) {
+	cases := []struct {
+		name       string
+		ni         *apps.NetworkIsolation
+		shouldFail bool
+	}{
+		{name: "unset"},
+		{name: "namespace", ni: &apps.NetworkIsolation{Mode: apps.NetworkIsolationNamespace, Dependencies: []string{"bar"}}},
+		{name: "unknown-mode", ni: &apps.NetworkIsolation{Mode: "Cluster"}, shouldFail: true},
+		{name: "invalid-dependency", ni: &apps.NetworkIsolation{Dependencies: []string{"not/an/app"}}, shouldFail: true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := validateNetworkIsolation(c.ni)
+			if err == nil && c.shouldFail {
+				t.Fatal("expected failure but got none")
+			}
+			if err != nil && !c.shouldFail {
+				t.Fatalf("unexpected error: %s", err)
+			}
+		})
+	}
+}
@@ -18,14 +18,18 @@ import (
 	"bytes"
 	"context"
 	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"log"
+	"net/http"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"sync"
+	"time"
 
 	apps "github.com/SAP/cloud-robotics/src/go/pkg/apis/apps/v1alpha1"
 	"github.com/SAP/cloud-robotics/src/go/pkg/synk"
@@ -78,9 +82,10 @@ type release struct {
 }
 
 type releaseStatus struct {
-	phase apps.ChartAssignmentPhase
-	err   error // last encountered error
-	retry bool  // whether deployment should be retried.
+	phase    apps.ChartAssignmentPhase
+	err      error     // last encountered error
+	retry    bool      // whether deployment should be retried.
+	accepted time.Time // when the last deployed generation was accepted.
 }
 
 // create release name for a chart assignment
@@ -103,6 +108,54 @@ func (rs *releases) status(as *apps.ChartAssignment) (releaseStatus, bool) {
 	return r.status, true
 }
 
+// releaseInfo is the debug representation of a cached release.
+type releaseInfo struct {
+	Name     string                    `json:"name"`
+	Phase    apps.ChartAssignmentPhase `json:"phase"`
+	Error    string                    `json:"error,omitempty"`
+	Retry    bool                      `json:"retry"`
+	Accepted *time.Time                `json:"accepted,omitempty"`
+}
+
+// ServeHTTP dumps the phase, last error and retry flag of all cached
+// releases as JSON.
+func (rs *releases) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	rs.mtx.Lock()
+	list := make([]*release, 0, len(rs.m))
+	for _, r := range rs.m {
+		list = append(list, r)
+	}
+	rs.mtx.Unlock()
+
+	infos := make([]releaseInfo, 0, len(list))
+	for _, r := range list {
+		r.mtx.Lock()
+		info := releaseInfo{
+			Name:  r.name,
+			Phase: r.status.phase,
+			Retry: r.status.retry,
+		}
+		if r.status.err != nil {
+			info.Error = r.status.err.Error()
+		}
+		if !r.status.accepted.IsZero() {
+			accepted := r.status.accepted
+			info.Accepted = &accepted
+		}
+		r.mtx.Unlock()
+		infos = append(infos, info)
+	}
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].Name < infos[j].Name
+	})
+	w.Header().Set("Content-Type", "application/json")
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(infos); err != nil {
+		log.Printf("encode releases: %s", err)
+	}
+}
+
 // add a release to the cache with an initial phase.
 func (rs *releases) add(as *apps.ChartAssignment) *release {
 	name := releaseName(as)
@@ -144,6 +197,11 @@ func (rs *releases) ensureUpdated(as *apps.ChartAssignment) bool {
 	asCopy := as.DeepCopy()
 	started := r.start(func() { r.update(asCopy) })
 	if started {
+		if r.generation != as.Generation {
+			r.mtx.Lock()
+			r.status.accepted = time.Now()
+			r.mtx.Unlock()
+		}
 		r.generation = as.Generation
 	}
 	return started
@@ -208,8 +266,12 @@ func (r *release) delete(as *apps.ChartAssignment) {
 	r.setPhase(apps.ChartAssignmentPhaseDeleting)
 	r.recorder.Event(as, core.EventTypeNormal, "DeleteChart", "deleting chart")
 
-	if err := r.synk.Delete(context.Background(), releaseName(as)); err != nil {
+	start := time.Now()
+	err := r.synk.Delete(context.Background(), releaseName(as))
+	observeStep(stepDelete, start)
+	if err != nil {
 		r.recorder.Event(as, core.EventTypeWarning, "Failure", err.Error())
+		recordFailure(stepDelete, synk.IsTransientErr(err))
 		r.setFailed(errors.Wrap(err, "delete release"), synk.IsTransientErr(err))
 	}
 	r.recorder.Event(as, core.EventTypeNormal, "Success", "chart deleted successfully")
@@ -221,9 +283,11 @@ func (r *release) delete(as *apps.ChartAssignment) {
 
 func (r *release) update(as *apps.ChartAssignment) {
 	r.setPhase(apps.ChartAssignmentPhaseLoadingChart)
+	r.recorder.Event(as, core.EventTypeNormal, "LoadChart", "load chart")
 	resources, retry, err := loadAndExpandChart(as)
 	if err != nil {
 		r.recorder.Event(as, core.EventTypeWarning, "Failure", err.Error())
+		recordFailure(failedStep(err), retry)
 		r.setFailed(err, retry)
 		return
 	}
@@ -244,9 +308,12 @@ func (r *release) update(as *apps.ChartAssignment) {
 				r.GetName(), msg)
 		},
 	}
+	start := time.Now()
 	_, err = r.synk.Apply(context.Background(), releaseName(as), opts, resources...)
+	observeStep(stepApply, start)
 	if err != nil {
 		r.recorder.Event(as, core.EventTypeWarning, "Failure", err.Error())
+		recordFailure(stepApply, synk.IsTransientErr(err))
 		r.setFailed(err, synk.IsTransientErr(err))
 		return
 	}
@@ -255,11 +322,27 @@ func (r *release) update(as *apps.ChartAssignment) {
 }
 
 func loadAndExpandChart(as *apps.ChartAssignment) ([]*unstructured.Unstructured, bool, error) {
+	start := time.Now()
 	c, values, err := loadChart(&as.Spec.Chart)
+	observeStep(stepFetch, start)
+	if err != nil {
+		return nil, isFetchError(err), &stepError{step: stepFetch, err: err}
+	}
+	if err := validateValues(c, values); err != nil {
+		return nil, false, &stepError{step: stepValidate, err: err}
+	}
+	start = time.Now()
+	res, err := expandChart(as, c, values)
+	observeStep(stepRender, start)
 	if err != nil {
-		return nil, true, err
+		return nil, false, &stepError{step: stepRender, err: err}
 	}
-	// Expand chart.
+	return res, false, nil
+}
+
+// expandChart renders the chart with the given values and decodes the
+// resulting manifests.
+func expandChart(as *apps.ChartAssignment, c *chart.Chart, values string) ([]*unstructured.Unstructured, error) {
 	manifests, err := renderutil.Render(c, &chart.Config{Raw: values}, renderutil.Options{
 		ReleaseOptions: chartutil.ReleaseOptions{
 			Name:      as.Name,
@@ -268,16 +351,28 @@ func loadAndExpandChart(as *apps.ChartAssignment) ([]*unstructured.Unstructured,
 		},
 	})
 	if err != nil {
-		return nil, false, errors.Wrap(err, "render chart")
+		return nil, errors.Wrap(err, "render chart")
 	}
 	// TODO: consider giving the synk package first-class support for raw manifests
 	// so that their decoding errors are fully surfaced in the ResourceSet. Otherwise,
 	// common YAML errors will only be surfaced one-by-one, which is tedious to handle.
-	res, err := decodeManifests(manifests)
-	if err != nil {
-		return nil, false, err
-	}
-	return res, false, nil
+	return decodeManifests(manifests)
+}
+
+// fetchError is returned by loadChart if the chart couldn't be retrieved from
+// its repository. Unlike errors decoding or parsing the chart, this may be a
+// transient error.
+type fetchError struct {
+	err error
+}
+
+func (e *fetchError) Error() string { return e.err.Error() }
+
+// isFetchError returns true if the chart of a failed loadChart call may load
+// on retry.
+func isFetchError(err error) bool {
+	_, ok := errors.Cause(err).(*fetchError)
+	return ok
 }
 
 func loadChart(cspec *apps.AssignedChart) (*chart.Chart, string, error) {
@@ -289,7 +384,7 @@ func loadChart(cspec *apps.AssignedChart) (*chart.Chart, string, error) {
 	} else {
 		archive, err = fetchChartTar(cspec.Repository, cspec.Name, cspec.Version)
 		if err != nil {
-			return nil, "", errors.Wrap(err, "retrieve chart")
+			return nil, "", &fetchError{err: errors.Wrap(err, "retrieve chart")}
 		}
 	}
 	c, err := chartutil.LoadArchive(archive)
@@ -306,21 +401,30 @@ func loadChart(cspec *apps.AssignedChart) (*chart.Chart, string, error) {
 		return nil, "", errors.Wrap(err, "load chart requirements")
 	}
 
-	// TODO: handle empty c.Values, cspec.Values
+	valsRaw, err := mergeValues(c, cspec.Values)
+	if err != nil {
+		return nil, "", err
+	}
+	return c, valsRaw, nil
+}
+
+// mergeValues merges the given values into the chart's default values and
+// returns the result as YAML.
+func mergeValues(c *chart.Chart, values apps.ConfigValues) (string, error) {
 	// Build the full set of values including the default ones. Even though
 	// they are part of the chart, they are ignored if we don't provide
 	// them explicitly.
-	vals, err := chartutil.ReadValues([]byte(c.Values.Raw))
+	vals, err := chartutil.ReadValues([]byte(c.GetValues().GetRaw()))
 	if err != nil {
-		return nil, "", errors.Wrap(err, "reading chart values")
+		return "", errors.Wrap(err, "reading chart values")
 	}
-	vals.MergeInto(chartutil.Values(cspec.Values)) // ChartAssignment values.
+	vals.MergeInto(chartutil.Values(values)) // ChartAssignment values.
 
 	valsRaw, err := vals.YAML()
 	if err != nil {
-		return nil, "", errors.Wrap(err, "encode values")
+		return "", errors.Wrap(err, "encode values")
 	}
-	return c, valsRaw, nil
+	return valsRaw, nil
 }
 
 func fetchChartTar(repoURL, name, version string) (io.Reader, error) {
@@ -15,6 +15,11 @@
 package chartassignment
 
 import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"strings"
 	"testing"
 
 	apps "github.com/SAP/cloud-robotics/src/go/pkg/apis/apps/v1alpha1"
@@ -141,3 +146,202 @@ spec:
 	// First apply, the chart should be installed.
 	r.delete(&as)
 }
+
+func Test_loadAndExpandChart_reportsRenderStep(t *testing.T) {
+	var as apps.ChartAssignment
+	unmarshalYAML(t, &as, `
+metadata:
+  name: test-assignment-1
+  namespace: default
+spec:
+  chart:
+    values:
+	`)
+	as.Spec.Chart.Inline = kubetest.BuildInlineChart(t, ChartName, `{{ .Values.foo.bar }}`, `foo: 1`)
+
+	_, retry, err := loadAndExpandChart(&as)
+	if err == nil {
+		t.Fatal("expected render error but got none")
+	}
+	if retry {
+		t.Errorf("expected render error not to be retried")
+	}
+	if got := failedStep(err); got != stepRender {
+		t.Errorf("failedStep() = %q, want %q", got, stepRender)
+	}
+}
+
+func Test_loadAndExpandChart_retriesOnlyFetchErrors(t *testing.T) {
+	cases := []struct {
+		name  string
+		chart apps.AssignedChart
+		retry bool
+	}{
+		{
+			name:  "invalid-base64",
+			chart: apps.AssignedChart{Inline: "not base64!"},
+		},
+		{
+			name:  "corrupt-archive",
+			chart: apps.AssignedChart{Inline: base64.StdEncoding.EncodeToString([]byte("not a tarball"))},
+		},
+		{
+			name: "unreachable-repository",
+			chart: apps.AssignedChart{
+				Repository: "http://127.0.0.1:1",
+				Name:       "foo",
+				Version:    "1.0.0",
+			},
+			retry: true,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			as := &apps.ChartAssignment{Spec: apps.ChartAssignmentSpec{Chart: c.chart}}
+
+			_, retry, err := loadAndExpandChart(as)
+			if err == nil {
+				t.Fatal("expected error but got none")
+			}
+			if retry != c.retry {
+				t.Errorf("loadAndExpandChart() retry = %v, want %v (err: %s)", retry, c.retry, err)
+			}
+			if retry, _ := validateChart(as); retry != c.retry {
+				t.Errorf("validateChart() retry = %v, want %v", retry, c.retry)
+			}
+		})
+	}
+}
+
+func Test_releases_ServeHTTP(t *testing.T) {
+	rs := &releases{m: map[string]*release{
+		"default.b": {name: "default.b", status: releaseStatus{
+			phase: apps.ChartAssignmentPhaseFailed,
+			err:   errors.New("boom"),
+			retry: true,
+		}},
+		"default.a": {name: "default.a", status: releaseStatus{
+			phase: apps.ChartAssignmentPhaseSettled,
+		}},
+	}}
+	rec := httptest.NewRecorder()
+	rs.ServeHTTP(rec, httptest.NewRequest("GET", "/debug/releases", nil))
+
+	var got []releaseInfo
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	want := []releaseInfo{
+		{Name: "default.a", Phase: apps.ChartAssignmentPhaseSettled},
+		{Name: "default.b", Phase: apps.ChartAssignmentPhaseFailed, Error: "boom", Retry: true},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d releases, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("release %d: got %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func Test_loadAndExpandChart_validatesValuesSchema(t *testing.T) {
+	var as apps.ChartAssignment
+	unmarshalYAML(t, &as, `
+metadata:
+  name: test-assignment-1
+  namespace: default
+spec:
+  chart:
+    values:
+      replicas: many
+	`)
+	as.Spec.Chart.Inline = kubetest.BuildInlineChartWithFiles(t, ChartName, map[string]string{
+		"values.yaml": `image: foo`,
+		"values.schema.json": `{
+  "$schema": "http://json-schema.org/draft-07/schema#",
+  "type": "object",
+  "required": ["image", "mapName"],
+  "properties": {
+    "image": {"type": "string"},
+    "mapName": {"type": "string"},
+    "replicas": {"type": "integer"}
+  }
+}`,
+	})
+
+	_, retry, err := loadAndExpandChart(&as)
+	if err == nil {
+		t.Fatal("expected schema validation error but got none")
+	}
+	if retry {
+		t.Errorf("expected schema validation error not to be retried")
+	}
+	if got := failedStep(err); got != stepValidate {
+		t.Errorf("failedStep() = %q, want %q", got, stepValidate)
+	}
+	// All violations must be reported.
+	for _, s := range []string{"mapName", "replicas"} {
+		if !strings.Contains(err.Error(), s) {
+			t.Errorf("expected error to mention %q, got: %s", s, err)
+		}
+	}
+
+	as.Spec.Chart.Values = apps.ConfigValues{"replicas": 2, "mapName": "warehouse"}
+	if _, _, err := loadAndExpandChart(&as); err != nil {
+		t.Fatalf("unexpected error for valid values: %s", err)
+	}
+}
+
+func Test_validateValues_schemaKeywords(t *testing.T) {
+	schema := `{
+  "$schema": "http://json-schema.org/draft-07/schema#",
+  "type": "object",
+  "properties": {
+    "mapName": {"type": ["string", "null"]},
+    "mode": {"const": "fleet"}
+  }
+}`
+	cases := []struct {
+		name       string
+		values     string
+		shouldFail bool
+	}{
+		{name: "null", values: `{mapName: null, mode: fleet}`},
+		{name: "string", values: `{mapName: warehouse, mode: fleet}`},
+		{name: "wrong-type", values: `{mapName: 1}`, shouldFail: true},
+		{name: "wrong-const", values: `{mode: single}`, shouldFail: true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			chart, err := chartutil.LoadArchive(base64.NewDecoder(base64.StdEncoding, strings.NewReader(
+				kubetest.BuildInlineChartWithFiles(t, ChartName, map[string]string{"values.schema.json": schema}))))
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = validateValues(chart, c.values)
+			if err == nil && c.shouldFail {
+				t.Fatal("expected failure but got none")
+			}
+			if err != nil && !c.shouldFail {
+				t.Fatalf("unexpected error: %s", err)
+			}
+		})
+	}
+}
+
+func Test_validateValues_skipsUnsupportedSchema(t *testing.T) {
+	for _, schema := range []string{
+		`{"type": "object",`,
+		`{"properties": {"foo": {"$ref": "#/definitions/foo"}}}`,
+	} {
+		chart, err := chartutil.LoadArchive(base64.NewDecoder(base64.StdEncoding, strings.NewReader(
+			kubetest.BuildInlineChartWithFiles(t, ChartName, map[string]string{"values.schema.json": schema}))))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := validateValues(chart, `foo: 1`); err != nil {
+			t.Errorf("schema %s: expected values not to be validated, got: %s", schema, err)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+// Copyright 2026 The Cloud Robotics Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chartassignment
+
+import (
+	"context"
+	"fmt"
+
+	apps "github.com/SAP/cloud-robotics/src/go/pkg/apis/apps/v1alpha1"
+	core "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Name of the ResourceQuota and LimitRange that are created in the app
+// namespace from spec.resources.
+const resourceLimitsName = "chartassignment-resources"
+
+// ensureResourceLimits creates, updates, or deletes the ResourceQuota and
+// LimitRange in the app namespace according to spec.resources.
+func (r *Reconciler) ensureResourceLimits(ctx context.Context, as *apps.ChartAssignment) error {
+	res := as.Spec.Resources
+	if res == nil {
+		res = &apps.ChartAssignmentResources{}
+	}
+	if err := r.ensureResourceQuota(ctx, as, res.Quota); err != nil {
+		return err
+	}
+	return r.ensureLimitRange(ctx, as, res.DefaultLimits, res.DefaultRequests)
+}
+
+func (r *Reconciler) ensureResourceQuota(ctx context.Context, as *apps.ChartAssignment, hard core.ResourceList) error {
+	var quota core.ResourceQuota
+	err := r.kube.Get(ctx, kclient.ObjectKey{Namespace: as.Spec.NamespaceName, Name: resourceLimitsName}, &quota)
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return fmt.Errorf("getting ResourceQuota \"%s:%s\" failed: %s", as.Spec.NamespaceName, resourceLimitsName, err)
+	}
+	exists := !k8serrors.IsNotFound(err)
+
+	if len(hard) == 0 {
+		if !exists {
+			return nil
+		}
+		if err := r.kube.Delete(ctx, &quota); err != nil && !k8serrors.IsNotFound(err) {
+			return fmt.Errorf("deleting ResourceQuota \"%s:%s\" failed: %s", as.Spec.NamespaceName, resourceLimitsName, err)
+		}
+		return nil
+	}
+	quota.Namespace = as.Spec.NamespaceName
+	quota.Name = resourceLimitsName
+	setLabel(&quota.ObjectMeta, "app", as.Name)
+	quota.Spec.Hard = hard
+
+	if !exists {
+		return r.kube.Create(ctx, &quota)
+	}
+	return r.kube.Update(ctx, &quota)
+}
+
+func (r *Reconciler) ensureLimitRange(ctx context.Context, as *apps.ChartAssignment, limits, requests core.ResourceList) error {
+	var lr core.LimitRange
+	err := r.kube.Get(ctx, kclient.ObjectKey{Namespace: as.Spec.NamespaceName, Name: resourceLimitsName}, &lr)
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return fmt.Errorf("getting LimitRange \"%s:%s\" failed: %s", as.Spec.NamespaceName, resourceLimitsName, err)
+	}
+	exists := !k8serrors.IsNotFound(err)
+
+	if len(limits) == 0 && len(requests) == 0 {
+		if !exists {
+			return nil
+		}
+		if err := r.kube.Delete(ctx, &lr); err != nil && !k8serrors.IsNotFound(err) {
+			return fmt.Errorf("deleting LimitRange \"%s:%s\" failed: %s", as.Spec.NamespaceName, resourceLimitsName, err)
+		}
+		return nil
+	}
+	lr.Namespace = as.Spec.NamespaceName
+	lr.Name = resourceLimitsName
+	setLabel(&lr.ObjectMeta, "app", as.Name)
+	lr.Spec.Limits = []core.LimitRangeItem{{
+		Type:           core.LimitTypeContainer,
+		Default:        limits,
+		DefaultRequest: requests,
+	}}
+
+	if !exists {
+		return r.kube.Create(ctx, &lr)
+	}
+	return r.kube.Update(ctx, &lr)
+}
+
+// quotaStatus returns the status of the ResourceQuota in the app namespace
+// or nil if no quota is configured.
+func (r *Reconciler) quotaStatus(ctx context.Context, as *apps.ChartAssignment) (*core.ResourceQuotaStatus, error) {
+	if as.Spec.Resources == nil || len(as.Spec.Resources.Quota) == 0 {
+		return nil, nil
+	}
+	var quota core.ResourceQuota
+	err := r.kube.Get(ctx, kclient.ObjectKey{Namespace: as.Spec.NamespaceName, Name: resourceLimitsName}, &quota)
+	if k8serrors.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	return &quota.Status, nil
+}
+
+// validateResources checks that default requests don't exceed default limits
+// and that the quota isn't negative.
+func validateResources(res *apps.ChartAssignmentResources) error {
+	if res == nil {
+		return nil
+	}
+	for name, req := range res.DefaultRequests {
+		if limit, ok := res.DefaultLimits[name]; ok && req.Cmp(limit) > 0 {
+			return fmt.Errorf("default request %s of %s exceeds default limit %s", req.String(), name, limit.String())
+		}
+	}
+	for name, q := range res.Quota {
+		if q.Sign() < 0 {
+			return fmt.Errorf("quota for %s must not be negative", name)
+		}
+	}
+	return nil
+}
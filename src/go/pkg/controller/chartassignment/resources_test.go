@@ -0,0 +1,95 @@
+// Copyright 2026 The Cloud Robotics Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chartassignment
+
+import (
+	"context"
+	"testing"
+
+	apps "github.com/SAP/cloud-robotics/src/go/pkg/apis/apps/v1alpha1"
+	core "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestEnsureResourceLimits(t *testing.T) {
+	ctx := context.Background()
+	sc := runtime.NewScheme()
+	scheme.AddToScheme(sc)
+	apps.AddToScheme(sc)
+	r := &Reconciler{kube: fake.NewClientBuilder().WithScheme(sc).Build()}
+
+	var as apps.ChartAssignment
+	unmarshalYAML(t, &as, `
+metadata:
+  name: foo
+  namespace: default
+spec:
+  clusterName: c1
+  namespaceName: app-foo
+  resources:
+    quota:
+      pods: 5
+    defaultLimits:
+      memory: 256Mi
+	`)
+	key := kclient.ObjectKey{Namespace: "app-foo", Name: resourceLimitsName}
+
+	if err := r.ensureResourceLimits(ctx, &as); err != nil {
+		t.Fatal(err)
+	}
+	var quota core.ResourceQuota
+	if err := r.kube.Get(ctx, key, &quota); err != nil {
+		t.Fatalf("get ResourceQuota: %s", err)
+	}
+	if got, want := quota.Spec.Hard[core.ResourcePods], resource.MustParse("5"); got.Cmp(want) != 0 {
+		t.Errorf("pod quota: got %s, want %s", got.String(), want.String())
+	}
+	var lr core.LimitRange
+	if err := r.kube.Get(ctx, key, &lr); err != nil {
+		t.Fatalf("get LimitRange: %s", err)
+	}
+	if got, want := lr.Spec.Limits[0].Default[core.ResourceMemory], resource.MustParse("256Mi"); got.Cmp(want) != 0 {
+		t.Errorf("default memory limit: got %s, want %s", got.String(), want.String())
+	}
+
+	// Updating the quota must be reflected.
+	as.Spec.Resources.Quota[core.ResourcePods] = resource.MustParse("10")
+	if err := r.ensureResourceLimits(ctx, &as); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.kube.Get(ctx, key, &quota); err != nil {
+		t.Fatalf("get ResourceQuota: %s", err)
+	}
+	if got, want := quota.Spec.Hard[core.ResourcePods], resource.MustParse("10"); got.Cmp(want) != 0 {
+		t.Errorf("pod quota: got %s, want %s", got.String(), want.String())
+	}
+
+	// Removing resources deletes both objects.
+	as.Spec.Resources = nil
+	if err := r.ensureResourceLimits(ctx, &as); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.kube.Get(ctx, key, &quota); !k8serrors.IsNotFound(err) {
+		t.Errorf("expected ResourceQuota to be deleted, got err %v", err)
+	}
+	if err := r.kube.Get(ctx, key, &lr); !k8serrors.IsNotFound(err) {
+		t.Errorf("expected LimitRange to be deleted, got err %v", err)
+	}
+}
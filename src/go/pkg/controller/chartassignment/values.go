@@ -0,0 +1,175 @@
+// Copyright 2026 The Cloud Robotics Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chartassignment
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+
+	apps "github.com/SAP/cloud-robotics/src/go/pkg/apis/apps/v1alpha1"
+	"github.com/pkg/errors"
+	"k8s.io/helm/pkg/chartutil"
+	"k8s.io/helm/pkg/proto/hapi/chart"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+	"k8s.io/kube-openapi/pkg/validation/strfmt"
+	"k8s.io/kube-openapi/pkg/validation/validate"
+)
+
+// valuesSchemaFile is the file in the chart's root directory that holds an
+// optional JSON schema for the chart's values.
+const valuesSchemaFile = "values.schema.json"
+
+// ValuesSchemaError is returned if chart values violate the chart's values
+// schema. It lists all violations.
+type ValuesSchemaError struct {
+	Errs []error
+}
+
+func (e *ValuesSchemaError) Error() string {
+	msgs := make([]string, 0, len(e.Errs))
+	for _, err := range e.Errs {
+		msgs = append(msgs, err.Error())
+	}
+	return fmt.Sprintf("values violate %s: %s", valuesSchemaFile, strings.Join(msgs, "; "))
+}
+
+// ValidateValues loads the given charts and validates their values merged
+// into the chart defaults against the charts' values schemas. Charts that
+// don't ship a usable schema are not validated. Each distinct chart is only
+// loaded once. A *ValuesSchemaError is returned for the first chart with
+// invalid values.
+func ValidateValues(cspecs ...*apps.AssignedChart) error {
+	loaded := map[string]*chart.Chart{}
+
+	for _, cspec := range cspecs {
+		key := strings.Join([]string{cspec.Repository, cspec.Name, cspec.Version, cspec.Inline}, "|")
+
+		c, ok := loaded[key]
+		if !ok {
+			var err error
+			if c, _, err = loadChart(&apps.AssignedChart{
+				Repository: cspec.Repository,
+				Name:       cspec.Name,
+				Version:    cspec.Version,
+				Inline:     cspec.Inline,
+			}); err != nil {
+				return err
+			}
+			loaded[key] = c
+		}
+		values, err := mergeValues(c, cspec.Values)
+		if err != nil {
+			return err
+		}
+		if err := validateValues(c, values); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateValues validates the YAML encoded values against the chart's
+// values schema. It returns nil if the chart has no schema. A schema that
+// can't be used for validation, e.g. because it contains a $ref, is logged
+// and the values are not validated.
+func validateValues(c *chart.Chart, valuesRaw string) error {
+	var raw []byte
+	for _, f := range c.Files {
+		if f.TypeUrl == valuesSchemaFile {
+			raw = f.Value
+			break
+		}
+	}
+	if raw == nil {
+		return nil
+	}
+	values, err := chartutil.ReadValues([]byte(valuesRaw))
+	if err != nil {
+		return errors.Wrap(err, "reading values")
+	}
+	schema, err := decodeValuesSchema(raw)
+	if err != nil {
+		log.Printf("Warning: values of chart %s not validated: %s", c.GetMetadata().GetName(), err)
+		return nil
+	}
+	// Round-trip through JSON so the validator sees the types of decoded
+	// JSON rather than those of the YAML decoder.
+	b, err := json.Marshal(values)
+	if err != nil {
+		return errors.Wrap(err, "encode values")
+	}
+	var obj interface{}
+	if err := json.Unmarshal(b, &obj); err != nil {
+		return errors.Wrap(err, "decode values")
+	}
+	res := validate.NewSchemaValidator(schema, nil, "", strfmt.Default).Validate(obj)
+	if res.HasErrors() {
+		return &ValuesSchemaError{Errs: res.Errors}
+	}
+	return nil
+}
+
+// decodeValuesSchema decodes a JSON schema for validation. The validator
+// doesn't know const, which is rewritten to an enum with a single value,
+// and doesn't resolve references.
+func decodeValuesSchema(raw []byte) (*spec.Schema, error) {
+	var doc interface{}
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return nil, errors.Wrapf(err, "decode %s", valuesSchemaFile)
+	}
+	if err := rewriteConst(doc); err != nil {
+		return nil, errors.Wrapf(err, "unsupported %s", valuesSchemaFile)
+	}
+	b, err := json.Marshal(doc)
+	if err != nil {
+		return nil, errors.Wrapf(err, "encode %s", valuesSchemaFile)
+	}
+	schema := &spec.Schema{}
+	if err := json.Unmarshal(b, schema); err != nil {
+		return nil, errors.Wrapf(err, "decode %s", valuesSchemaFile)
+	}
+	return schema, nil
+}
+
+// rewriteConst replaces const keywords in the decoded schema by enums. It
+// returns an error if the schema contains a reference.
+func rewriteConst(doc interface{}) error {
+	switch v := doc.(type) {
+	case map[string]interface{}:
+		if ref, ok := v["$ref"]; ok {
+			return fmt.Errorf("reference %v", ref)
+		}
+		if c, ok := v["const"]; ok {
+			if _, ok := v["enum"]; !ok {
+				v["enum"] = []interface{}{c}
+			}
+			delete(v, "const")
+		}
+		for _, e := range v {
+			if err := rewriteConst(e); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		for _, e := range v {
+			if err := rewriteConst(e); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -299,6 +299,16 @@ func (f *Fixture) FromYAML(tmpl string, vals, dst interface{}) {
 // template and values.
 func BuildInlineChart(t *testing.T, name, tmpl, values string) string {
 	t.Helper()
+	return BuildInlineChartWithFiles(t, name, map[string]string{
+		"templates/template.yaml": tmpl,
+		"values.yaml":             values,
+	})
+}
+
+// BuildInlineChartWithFiles creates an inline chart string with the given
+// name and files. Paths are relative to the chart directory.
+func BuildInlineChartWithFiles(t *testing.T, name string, files map[string]string) string {
+	t.Helper()
 
 	chartData := fmt.Sprintf(`{name: %q, version: "0.0.1"}`, name)
 
@@ -309,8 +319,9 @@ func BuildInlineChart(t *testing.T, name, tmpl, values string) string {
 	if err := addFileToTar(tw, name+"/Chart.yaml", chartData); err != nil {
 		t.Fatalf("Failed to add Chart.yaml to tarball: %s", err)
 	}
-	addFileToTar(tw, name+"/templates/template.yaml", tmpl)
-	addFileToTar(tw, name+"/values.yaml", values)
+	for path, content := range files {
+		addFileToTar(tw, name+"/"+path, content)
+	}
 	tw.Close()
 	zw.Close()
 	bw.Close()
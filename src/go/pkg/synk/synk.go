@@ -423,10 +423,8 @@ func (s *Synk) initialize(
 	// TODO: consider putting this and other validation as a step after initialize
 	// so we can give validation errors in batch in the ResourceSet status.
 	if opts.EnforceNamespace {
-		for _, r := range regulars {
-			if ns := r.GetNamespace(); ns != "" && ns != opts.Namespace && ns != "kube-system" {
-				return nil, nil, errors.Errorf("invalid namespace %q on %q, expected %q or \"kube-system\"", ns, resourceKey(r), opts.Namespace)
-			}
+		if err := ValidateNamespaces(opts.Namespace, regulars...); err != nil {
+			return nil, nil, err
 		}
 	}
 
@@ -997,6 +995,22 @@ func isCustomResourceDefinition(r *unstructured.Unstructured) bool {
 	return strings.HasPrefix(r.GetAPIVersion(), "apiextensions.k8s.io/") && r.GetKind() == "CustomResourceDefinition"
 }
 
+// ValidateNamespaces returns an error if a resource that is not a
+// CustomResourceDefinition has a namespace set that's different from the
+// given namespace and "kube-system". This is the check Apply runs with
+// EnforceNamespace.
+func ValidateNamespaces(namespace string, resources ...*unstructured.Unstructured) error {
+	for _, r := range resources {
+		if isCustomResourceDefinition(r) {
+			continue
+		}
+		if ns := r.GetNamespace(); ns != "" && ns != namespace && ns != "kube-system" {
+			return errors.Errorf("invalid namespace %q on %q, expected %q or \"kube-system\"", ns, resourceKey(r), namespace)
+		}
+	}
+	return nil
+}
+
 func separateCRDsFromResources(resources []*unstructured.Unstructured) (crds []*unstructured.Unstructured, regulars []*unstructured.Unstructured) {
 	for _, r := range resources {
 		if isCustomResourceDefinition(r) {